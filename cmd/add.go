@@ -9,11 +9,19 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var addFrom string
+
 var addCmd = &cobra.Command{
-	Use:   "add <branch>",
+	Use:   "add <branch> [--from <ref>]",
 	Short: "Add a new worktree",
-	Long:  "Add a new git worktree in the worktrees/ subdirectory.",
-	Args:  cobra.ExactArgs(1),
+	Long: `Add a new git worktree at the path given by the repository's layout (worktrees/<name> by default).
+
+The branch is created from HEAD if it does not exist, or tracking the
+remote-tracking branch of the same name if exactly one remote has it. With
+--from, it is created at the given commit, branch or tag instead, and must not
+exist yet.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAddBranch,
 	Run: func(cmd *cobra.Command, args []string) {
 		branch := args[0]
 
@@ -27,7 +35,7 @@ var addCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		worktreePath, err := manager.AddWorktree(repoPath, branch)
+		worktreePath, err := manager.AddWorktreeFrom(repoPath, branch, startPoint(gitService, repoPath, branch, addFrom))
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error adding worktree: %v\n", err)
 			os.Exit(1)
@@ -37,7 +45,37 @@ var addCmd = &cobra.Command{
 	},
 }
 
+func init() {
+	addCmd.Flags().StringVar(&addFrom, "from", "", "Create the branch at this commit, branch or tag")
+	_ = addCmd.RegisterFlagCompletionFunc("from", completeRefs)
+}
+
+// startPoint returns where a new branch should start: from if given,
+// otherwise the only remote-tracking branch of the same name, so adding a
+// branch that exists only on a remote tracks it. Empty means HEAD.
+func startPoint(service *internal.GitService, repoPath, branch, from string) string {
+	if from != "" {
+		return from
+	}
+	branches, err := service.ListBranches(repoPath)
+	if err != nil {
+		return ""
+	}
+	ref, _ := internal.TrackingStartPoint(branches, branch)
+	return ref
+}
+
+// getRepoRoot returns the path the worktree layout is relative to: the main
+// worktree, or the git directory of a bare repository. This is the same
+// from any worktree of the repository.
 func getRepoRoot(runner internal.CommandRunner) (string, error) {
+	// git always lists the main worktree (or the bare repository) first
+	if output, err := runner.Run("git worktree list --porcelain"); err == nil {
+		if entries := internal.ParseWorktreeList(output); len(entries) > 0 {
+			return entries[0].Path, nil
+		}
+	}
+
 	output, err := runner.Run("git rev-parse --show-toplevel")
 	if err != nil {
 		return "", fmt.Errorf("not in a git repository: %w", err)
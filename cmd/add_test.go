@@ -2,14 +2,17 @@ package cmd
 
 import (
 	"testing"
+
+	"github.com/no-yan/wt/internal"
 )
 
 func TestGetRepoRoot(t *testing.T) {
 	tests := []struct {
-		name      string
-		gitOutput string
-		want      string
-		wantErr   bool
+		name         string
+		gitOutput    string
+		worktreeList string
+		want         string
+		wantErr      bool
 	}{
 		{
 			name:      "valid repo",
@@ -23,6 +26,19 @@ func TestGetRepoRoot(t *testing.T) {
 			want:      "/repo path/project",
 			wantErr:   false,
 		},
+		{
+			name:         "linked worktree resolves to main worktree",
+			gitOutput:    "/repo/worktrees/feature\n",
+			worktreeList: "worktree /repo\nHEAD abc123\nbranch refs/heads/main\n\nworktree /repo/worktrees/feature\nHEAD def456\nbranch refs/heads/feature\n",
+			want:         "/repo",
+			wantErr:      false,
+		},
+		{
+			name:         "bare repository",
+			worktreeList: "worktree /src/app.git\nbare\n\nworktree /src/app.worktrees/main\nHEAD abc123\nbranch refs/heads/main\n",
+			want:         "/src/app.git",
+			wantErr:      false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -30,6 +46,7 @@ func TestGetRepoRoot(t *testing.T) {
 			mockRunner := &testMockCommandRunner{
 				outputs: map[string]string{
 					"git rev-parse --show-toplevel": tt.gitOutput,
+					"git worktree list --porcelain": tt.worktreeList,
 				},
 			}
 
@@ -47,6 +64,65 @@ func TestGetRepoRoot(t *testing.T) {
 	}
 }
 
+func TestStartPoint(t *testing.T) {
+	const listCmd = "git -C /repo for-each-ref --format='%(refname)' refs/heads refs/remotes"
+	tests := []struct {
+		name     string
+		branches string
+		branch   string
+		from     string
+		want     string
+	}{
+		{
+			name:     "branch only on a remote tracks it",
+			branches: "refs/heads/main\nrefs/remotes/origin/feature\n",
+			branch:   "feature",
+			want:     "origin/feature",
+		},
+		{
+			name:     "existing local branch is checked out",
+			branches: "refs/heads/feature\nrefs/remotes/origin/feature\n",
+			branch:   "feature",
+			want:     "",
+		},
+		{
+			name:     "branch on several remotes starts at HEAD",
+			branches: "refs/remotes/origin/feature\nrefs/remotes/upstream/feature\n",
+			branch:   "feature",
+			want:     "",
+		},
+		{
+			name:     "new branch starts at HEAD",
+			branches: "refs/heads/main\n",
+			branch:   "feature",
+			want:     "",
+		},
+		{
+			name:     "from wins over the remote branch",
+			branches: "refs/remotes/origin/feature\n",
+			branch:   "feature",
+			from:     "v1.0",
+			want:     "v1.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRunner := &testMockCommandRunner{
+				outputs: map[string]string{
+					listCmd:               tt.branches,
+					"git -C /repo remote": "origin\nupstream\n",
+				},
+			}
+
+			got := startPoint(internal.NewGitService(mockRunner), "/repo", tt.branch, tt.from)
+			if got != tt.want {
+				t.Errorf("startPoint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 type testMockCommandRunner struct {
 	outputs map[string]string
 }
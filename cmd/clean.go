@@ -2,15 +2,24 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"slices"
+	"unicode/utf8"
 
 	"github.com/no-yan/wt/internal"
 	"github.com/spf13/cobra"
 )
 
 var (
-	cleanDryRun bool
-	cleanForce  bool
+	cleanDryRun       bool
+	cleanForce        bool
+	cleanMerged       bool
+	cleanInto         string
+	cleanDeleteBranch bool
+	cleanGone         bool
+	cleanFetch        bool
+	cleanArchive      bool
 )
 
 var cleanCmd = &cobra.Command{
@@ -18,38 +27,84 @@ var cleanCmd = &cobra.Command{
 	Short: "Clean up stale worktrees",
 	Long: `Clean up stale worktrees that are no longer valid.
 
-This command removes worktree entries that point to non-existent directories
-or directories that are no longer valid git worktrees.
+This command finds worktree entries that point to non-existent directories or
+directories that are no longer valid git worktrees, directories inside the
+managed root that git does not know at a path wt.pathTemplate could have
+produced, and registered worktrees outside the managed root. For each one it
+asks whether to delete it, re-register it or ignore it, and acts on exactly
+that entry. Locked worktrees are left alone.
+
+Worktrees that only lost their links because they or the repository were moved
+are never deleted here; 'wt repair' fixes them.
+
+With --merged, it instead removes clean worktrees whose branches have landed
+in the base branch (wt.baseBranch, the branch checked out in the main worktree,
+or the one given with --into). Besides regular merges this detects branches
+that were rebased or squash-merged, by comparing patch-ids and trees. Each
+worktree is listed with the evidence before anything is removed; locked and
+detached worktrees are left alone, as are branches at the tip of the base
+branch, which have no commits of their own yet. --delete-branch deletes the
+branches too.
+
+With --gone, it removes clean worktrees whose branch tracks an upstream branch
+that has been deleted, as happens when a merged pull request's branch is
+deleted. --fetch runs 'git fetch --all --prune' first so that deletions on the
+remote are seen. A gone upstream does not mean the local commits landed, so
+these worktrees go through the checks of 'wt remove': unpushed commits are
+refused unless --archive bundles them first, and --delete-branch only deletes
+branches merged into the base branch. --gone and --merged can be combined.
 
 Use --dry-run to see what would be cleaned without actually removing anything.
-Use --force to skip confirmation prompts.`,
+Use --force to skip confirmation prompts: stale entries are deleted and
+everything else is ignored.`,
+	Args:              cobra.NoArgs,
+	ValidArgsFunction: cobra.NoFileCompletions,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if !cleanMerged && cmd.Flags().Changed("into") {
+			return fmt.Errorf("--into requires --merged")
+		}
+		if !cleanGone && cleanFetch {
+			return fmt.Errorf("--fetch requires --gone")
+		}
+		if !cleanGone && cleanArchive {
+			return fmt.Errorf("--archive requires --gone")
+		}
+		if !cleanMerged && !cleanGone && cleanDeleteBranch {
+			return fmt.Errorf("--delete-branch requires --merged or --gone")
+		}
+		return nil
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		runner := internal.NewExecCommandRunner()
 		service := internal.NewGitService(runner)
 
-		worktrees, err := service.ListWorktrees()
+		if cleanMerged || cleanGone {
+			cleanBranchWorktrees(runner, service)
+			return
+		}
+
+		manager := internal.NewWorktreeManager(service, runner)
+		repoPath, err := getRepoRoot(runner)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error listing worktrees: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error finding git repository: %v\n", err)
 			os.Exit(1)
 		}
 
-		// Find stale worktrees
-		var staleWorktrees []internal.Worktree
-		for _, wt := range worktrees {
-			if wt.Status == internal.StatusStale {
-				staleWorktrees = append(staleWorktrees, wt)
-			}
+		entries, err := manager.FindCleanupEntries(repoPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing worktrees: %v\n", err)
+			os.Exit(1)
 		}
 
-		if len(staleWorktrees) == 0 {
-			fmt.Println("No stale worktrees found.")
+		if len(entries) == 0 {
+			fmt.Println("No stale or orphaned worktrees found.")
 			return
 		}
 
 		// Show what will be cleaned
-		fmt.Printf("Found %d stale worktree(s):\n", len(staleWorktrees))
-		for _, wt := range staleWorktrees {
-			fmt.Printf("  %s -> %s\n", wt.Name(), wt.Path)
+		fmt.Printf("Found %d worktree(s) to clean:\n", len(entries))
+		for _, entry := range entries {
+			fmt.Printf("  %s -> %s (%s)\n", entry.Name, entry.Path, entry.Reason)
 		}
 
 		if cleanDryRun {
@@ -57,36 +112,236 @@ Use --force to skip confirmation prompts.`,
 			return
 		}
 
-		// Confirm before proceeding
-		if !cleanForce {
-			fmt.Print("\nProceed with cleanup? (y/N): ")
-			var response string
-			_, _ = fmt.Scanln(&response)
-			if response != "y" && response != "Y" {
-				fmt.Println("Cleanup cancelled.")
-				return
+		// Act on exactly the listed entries, one at a time
+		fmt.Println()
+		failed := 0
+		for _, entry := range entries {
+			if entry.Repairable {
+				fmt.Printf("Skipped %s: run 'wt repair' to fix its links\n", entry.Name)
+				continue
+			}
+
+			// Without prompts only stale entries are deleted; files and
+			// worktrees are never deleted without an answer
+			action := cleanupActionIgnore
+			switch {
+			case !cleanForce:
+				action = askCleanupAction(entry)
+			case entry.Kind == internal.CleanupStale:
+				action = cleanupActionDelete
 			}
-		}
 
-		// Clean up stale worktrees using git worktree prune
-		if err := pruneStaleWorktrees(runner); err != nil {
-			fmt.Fprintf(os.Stderr, "Error cleaning stale worktrees: %v\n", err)
+			if err := applyCleanupAction(manager, repoPath, entry, action); err != nil {
+				fmt.Fprintf(os.Stderr, "Error cleaning %s: %v\n", entry.Name, err)
+				failed++
+			}
+		}
+		if failed > 0 {
 			os.Exit(1)
 		}
-
-		fmt.Printf("Cleaned %d stale worktree(s).\n", len(staleWorktrees))
 	},
 }
 
+type cleanupAction int
+
+const (
+	cleanupActionIgnore cleanupAction = iota
+	cleanupActionDelete
+	cleanupActionReregister
+)
+
+// askCleanupAction prompts for what to do with entry. Anything but an
+// explicit choice ignores it.
+func askCleanupAction(entry internal.CleanupEntry) cleanupAction {
+	deleteHint := map[internal.CleanupKind]string{
+		internal.CleanupStale:   "forget the entry",
+		internal.CleanupOrphan:  "delete the directory and its files",
+		internal.CleanupOutside: "remove the worktree",
+	}[entry.Kind]
+	reregisterHint := map[internal.CleanupKind]string{
+		internal.CleanupStale:   "repair or check it out again",
+		internal.CleanupOrphan:  "register it as a worktree",
+		internal.CleanupOutside: "move it into the managed directory",
+	}[entry.Kind]
+
+	fmt.Printf("%s: [d]elete (%s), [r]e-register (%s) or [i]gnore? (i): ", entry.Name, deleteHint, reregisterHint)
+	var response string
+	_, _ = fmt.Scanln(&response)
+	switch response {
+	case "d", "D":
+		return cleanupActionDelete
+	case "r", "R":
+		return cleanupActionReregister
+	default:
+		return cleanupActionIgnore
+	}
+}
+
+func applyCleanupAction(manager *internal.WorktreeManager, repoPath string, entry internal.CleanupEntry, action cleanupAction) error {
+	switch action {
+	case cleanupActionDelete:
+		if err := manager.DeleteCleanupEntry(repoPath, entry); err != nil {
+			return err
+		}
+		fmt.Printf("Deleted %s\n", entry.Name)
+	case cleanupActionReregister:
+		path, err := manager.ReregisterCleanupEntry(repoPath, entry)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Re-registered %s -> %s\n", entry.Name, path)
+	default:
+		fmt.Printf("Ignored %s\n", entry.Name)
+	}
+	return nil
+}
+
 func init() {
 	cleanCmd.Flags().BoolVar(&cleanDryRun, "dry-run", false, "Show what would be cleaned without making changes")
 	cleanCmd.Flags().BoolVar(&cleanForce, "force", false, "Skip confirmation prompts")
+	cleanCmd.Flags().BoolVar(&cleanMerged, "merged", false, "Remove clean worktrees whose branches are merged")
+	cleanCmd.Flags().StringVar(&cleanInto, "into", "", "Base branch to check merges against (with --merged)")
+	_ = cleanCmd.RegisterFlagCompletionFunc("into", completeLocalBranches)
+	cleanCmd.Flags().BoolVar(&cleanDeleteBranch, "delete-branch", false, "Also delete the branches (with --merged or --gone)")
+	cleanCmd.Flags().BoolVar(&cleanGone, "gone", false, "Remove clean worktrees whose upstream branch was deleted")
+	cleanCmd.Flags().BoolVar(&cleanFetch, "fetch", false, "Fetch all remotes with --prune first (with --gone)")
+	cleanCmd.Flags().BoolVar(&cleanArchive, "archive", false, "Archive unpushed commits of --gone worktrees instead of refusing them")
 }
 
-func pruneStaleWorktrees(runner internal.CommandRunner) error {
-	// Use git worktree prune to remove stale worktree entries
-	_, err := runner.Run("git worktree prune")
-	return err
+// cleanBranchWorktrees removes the worktrees selected by --merged and
+// --gone after listing them with their evidence.
+func cleanBranchWorktrees(runner internal.CommandRunner, service *internal.GitService) {
+	manager := internal.NewWorktreeManager(service, runner)
+
+	repoPath, err := getRepoRoot(runner)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding git repository: %v\n", err)
+		os.Exit(1)
+	}
+
+	var gone, merged []internal.CleanCandidate
+	if cleanGone {
+		if gone, err = manager.FindGoneWorktrees(repoPath, cleanFetch); err != nil {
+			fmt.Fprintf(os.Stderr, "Error finding worktrees with gone upstreams: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if cleanMerged {
+		if merged, err = manager.FindMergedWorktrees(repoPath, cleanInto); err != nil {
+			fmt.Fprintf(os.Stderr, "Error finding merged worktrees: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	// A merged worktree is listed once, with the evidence of its merge
+	gone = slices.DeleteFunc(gone, func(g internal.CleanCandidate) bool {
+		return slices.ContainsFunc(merged, func(m internal.CleanCandidate) bool { return m.Name == g.Name })
+	})
+	candidates := append(slices.Clone(gone), merged...)
+
+	kind := "merged"
+	switch {
+	case cleanGone && cleanMerged:
+		kind = "merged or gone"
+	case cleanGone:
+		kind = "gone"
+	}
+
+	if len(candidates) == 0 {
+		fmt.Printf("No %s worktrees found.\n", kind)
+		return
+	}
+
+	fmt.Printf("Found %d %s worktree(s):\n", len(candidates), kind)
+	formatCleanCandidates(candidates, os.Stdout)
+
+	if cleanDryRun {
+		fmt.Println("\nDry run mode - no changes made.")
+		return
+	}
+
+	if !cleanForce {
+		fmt.Print("\nProceed with cleanup? (y/N): ")
+		var response string
+		_, _ = fmt.Scanln(&response)
+		if response != "y" && response != "Y" {
+			fmt.Println("Cleanup cancelled.")
+			return
+		}
+	}
+
+	results, err := removeCleanCandidates(manager, repoPath, gone, merged, cleanDeleteBranch, cleanArchive)
+	for _, result := range results {
+		printRemoveResult(result)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error removing worktree: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Cleaned %d %s worktree(s).\n", len(candidates), kind)
+}
+
+// removeCleanCandidates removes the gone and merged worktrees. Merged
+// branches have landed, though git may not see squashed ones as merged, so
+// their commits are archived and their branches force-deleted. A gone
+// upstream says nothing about local commits: those worktrees get the checks
+// of wt remove, so unpushed commits are refused unless archive is set and
+// only merged branches are deleted. Every target is validated before any
+// is removed.
+func removeCleanCandidates(manager *internal.WorktreeManager, repoPath string, gone, merged []internal.CleanCandidate, deleteBranch, archive bool) ([]internal.RemoveResult, error) {
+	type batch struct {
+		names []string
+		opts  internal.RemoveOptions
+	}
+	batches := []batch{
+		{names: cleanCandidateNames(gone), opts: internal.RemoveOptions{DeleteBranch: deleteBranch, Archive: archive}},
+		{names: cleanCandidateNames(merged), opts: internal.RemoveOptions{ForceDeleteBranch: deleteBranch, Archive: true}},
+	}
+
+	for _, b := range batches {
+		if len(b.names) == 0 {
+			continue
+		}
+		dryRun := b.opts
+		dryRun.DryRun = true
+		if _, err := manager.RemoveWorktrees(repoPath, b.names, dryRun); err != nil {
+			return nil, err
+		}
+	}
+
+	var results []internal.RemoveResult
+	for _, b := range batches {
+		if len(b.names) == 0 {
+			continue
+		}
+		removed, err := manager.RemoveWorktrees(repoPath, b.names, b.opts)
+		results = append(results, removed...)
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+func cleanCandidateNames(candidates []internal.CleanCandidate) []string {
+	names := make([]string, len(candidates))
+	for i, wt := range candidates {
+		names[i] = wt.Name
+	}
+	return names
+}
+
+func formatCleanCandidates(candidates []internal.CleanCandidate, w io.Writer) {
+	var nameWidth, branchWidth int
+	for _, wt := range candidates {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(wt.Name))
+		branchWidth = max(branchWidth, utf8.RuneCountInString(wt.Branch))
+	}
+	for _, wt := range candidates {
+		if _, err := fmt.Fprintf(w, "  %-*s  %-*s  %s\n", nameWidth, wt.Name, branchWidth, wt.Branch, wt.Evidence); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		}
+	}
 }
 
 func shellescape(s string) string {
@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/no-yan/wt/internal"
@@ -139,31 +141,29 @@ func TestCleanIntegration(t *testing.T) {
 	})
 
 	t.Run("Clean stale worktree", func(t *testing.T) {
-		// Get the stale worktree path
-		worktrees, err := gitService.ListWorktrees()
+		var staleEntry *internal.CleanupEntry
+		entries, err := manager.FindCleanupEntries(tempDir)
 		if err != nil {
-			t.Fatalf("Failed to list worktrees: %v", err)
+			t.Fatalf("Failed to find cleanup entries: %v", err)
 		}
-
-		var staleWorktree *internal.Worktree
-		for _, wt := range worktrees {
-			if wt.Name() == "feature-stale-test" && wt.Status == internal.StatusStale {
-				staleWorktree = &wt
+		for _, entry := range entries {
+			if entry.Name == "feature-stale-test" && entry.Kind == internal.CleanupStale {
+				staleEntry = &entry
 				break
 			}
 		}
 
-		if staleWorktree == nil {
+		if staleEntry == nil {
 			t.Fatal("No stale worktree found to clean")
 		}
 
-		// Clean the stale worktree
-		if err := pruneStaleWorktrees(runner); err != nil {
+		// Clean only the stale worktree's entry
+		if err := manager.DeleteCleanupEntry(tempDir, *staleEntry); err != nil {
 			t.Fatalf("Failed to clean stale worktree: %v", err)
 		}
 
 		// Verify the worktree is no longer listed
-		worktrees, err = gitService.ListWorktrees()
+		worktrees, err := gitService.ListWorktrees()
 		if err != nil {
 			t.Fatalf("Failed to list worktrees after cleaning: %v", err)
 		}
@@ -175,3 +175,85 @@ func TestCleanIntegration(t *testing.T) {
 		}
 	})
 }
+
+// A branch whose upstream was deleted may still hold commits that never
+// left the machine; wt clean --gone must not drop them silently.
+func TestCleanGoneIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	if shouldSkipIntegrationTest() {
+		t.Skip("Skipping integration test in problematic environment")
+	}
+
+	tempDir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	origin := filepath.Join(tempDir, "origin.git")
+	repo := filepath.Join(tempDir, "repo")
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test User", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test User", "GIT_COMMITTER_EMAIL=test@example.com")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	git(tempDir, "init", "--bare", "-b", "main", origin)
+	git(tempDir, "clone", origin, repo)
+	git(repo, "commit", "--allow-empty", "-m", "Initial commit")
+	git(repo, "push", "origin", "HEAD:main")
+	git(repo, "checkout", "-b", "feature")
+	git(repo, "commit", "--allow-empty", "-m", "Feature")
+	git(repo, "push", "-u", "origin", "feature")
+	git(repo, "checkout", "main")
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(originalDir) }()
+	if err := os.Chdir(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := internal.NewExecCommandRunner()
+	manager := internal.NewWorktreeManager(internal.NewGitService(runner), runner)
+	worktreePath, err := manager.AddWorktree(repo, "feature")
+	if err != nil {
+		t.Fatalf("AddWorktree() error = %v", err)
+	}
+	// A local-only commit, then the upstream branch is deleted
+	git(worktreePath, "commit", "--allow-empty", "-m", "Work in progress")
+	git(repo, "push", "origin", "--delete", "feature")
+
+	gone, err := manager.FindGoneWorktrees(repo, false)
+	if err != nil {
+		t.Fatalf("FindGoneWorktrees() error = %v", err)
+	}
+	if len(gone) != 1 || gone[0].Name != "feature" {
+		t.Fatalf("FindGoneWorktrees() = %+v, want feature", gone)
+	}
+
+	if _, err := removeCleanCandidates(manager, repo, gone, nil, false, false); err == nil || !strings.Contains(err.Error(), "not in main") {
+		t.Errorf("removeCleanCandidates() error = %v, want unpushed commits refused", err)
+	}
+	if _, err := removeCleanCandidates(manager, repo, gone, nil, true, true); err == nil || !strings.Contains(err.Error(), "not merged") {
+		t.Errorf("removeCleanCandidates() with --archive error = %v, want the unmerged branch kept", err)
+	}
+	if _, err := os.Stat(worktreePath); err != nil {
+		t.Fatalf("worktree was removed despite the refusal: %v", err)
+	}
+
+	results, err := removeCleanCandidates(manager, repo, gone, nil, false, true)
+	if err != nil {
+		t.Fatalf("removeCleanCandidates() with --archive error = %v", err)
+	}
+	if len(results) != 1 || results[0].ArchivePath == "" || results[0].BranchDeleted {
+		t.Errorf("results = %+v, want feature archived with its branch kept", results)
+	}
+}
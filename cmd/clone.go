@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/no-yan/wt/internal"
+	"github.com/spf13/cobra"
+)
+
+var cloneBare bool
+
+var cloneCmd = &cobra.Command{
+	Use:   "clone <url-or-path> [dir]",
+	Short: "Clone a repository into wt's layout",
+	Long: `Clone a repository and configure it for wt.
+
+By default this is a regular clone using the worktrees/ layout.
+
+With --bare the repository is cloned into <dir>/.bare and every branch lives
+in its own worktree directly inside <dir>:
+
+  app/
+  ├── .bare/   # bare git directory
+  ├── .git     # points git at .bare
+  └── main/    # worktree of the default branch
+
+The directory defaults to the repository name, as with git clone.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		source := args[0]
+
+		dir := internal.CloneDirName(source)
+		if len(args) == 2 {
+			dir = args[1]
+		}
+		if dir == "" {
+			fmt.Fprintf(os.Stderr, "Error: cannot derive a directory name from %q, please specify one\n", source)
+			os.Exit(1)
+		}
+
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		runner := internal.NewExecCommandRunner()
+		gitService := internal.NewGitService(runner)
+		manager := internal.NewWorktreeManager(gitService, runner)
+
+		result, err := manager.Clone(source, dir, cloneBare)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error cloning repository: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Cloned %s into %s\n", source, dir)
+		if cloneBare {
+			fmt.Printf("Added worktree: %s\n", result.WorktreePath)
+		}
+	},
+}
+
+func init() {
+	cloneCmd.Flags().BoolVar(&cloneBare, "bare", false, "Clone as a bare repository with a worktree per branch")
+}
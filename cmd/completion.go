@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/no-yan/wt/internal"
+	"github.com/spf13/cobra"
+)
+
+// Completion functions run on every <TAB>, through the hidden __complete
+// command that the scripts from 'wt shell-init' call. Failures complete
+// nothing, as there is nowhere to report them.
+
+// completionRepo returns the services and repository root for a completion.
+func completionRepo() (*internal.WorktreeManager, *internal.GitService, string, error) {
+	runner := internal.NewExecCommandRunner()
+	service := internal.NewGitService(runner)
+	repoPath, err := getRepoRoot(runner)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return internal.NewWorktreeManager(service, runner), service, repoPath, nil
+}
+
+// completeWorktrees completes the names of worktrees not given yet.
+func completeWorktrees(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	_, service, _, err := completionRepo()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	worktrees, err := service.ListWorktrees()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return worktreeCompletions(worktrees, args), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSwitchTarget completes the worktrees most recently used first,
+// as in the picker, or the branches for --create.
+func completeSwitchTarget(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	manager, service, repoPath, err := completionRepo()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	if switchCreate {
+		branches, err := service.ListBranches(repoPath)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return branchCompletions(branches, nil), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	worktrees, err := service.ListWorktrees()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	resolver := newResolver(manager, repoPath, worktrees, false)
+	var current string
+	if wt, ok := internal.FindWorktreeByPath(worktrees, resolver.Dir); ok {
+		current = wt.Path
+	}
+	var ordered []internal.Worktree
+	for _, recent := range internal.RecentWorktrees(worktrees, resolver.History, current, true) {
+		ordered = append(ordered, recent.Worktree)
+	}
+	if wt, ok := internal.FindWorktreeByPath(worktrees, current); ok {
+		ordered = append(ordered, *wt)
+	}
+	return worktreeCompletions(ordered, nil), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeRemoveTargets completes the worktrees wt remove would accept with
+// the flags given so far.
+func completeRemoveTargets(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	_, service, repoPath, err := completionRepo()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	worktrees, err := service.ListWorktrees()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	layout, err := internal.LoadLayout(internal.NewExecCommandRunner(), repoPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	removable := internal.RemovableWorktrees(layout, worktrees, internal.RemoveOptions{Force: removeForce})
+	return worktreeCompletions(removable, args), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeAddBranch completes the branches without a worktree.
+func completeAddBranch(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	_, service, repoPath, err := completionRepo()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	branches, err := service.ListBranches(repoPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	worktrees, err := service.ListWorktrees()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return branchCompletions(branches, worktrees), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRefs completes local and remote-tracking branches, for flags
+// taking a start point.
+func completeRefs(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	_, service, repoPath, err := completionRepo()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	branches, err := service.ListBranches(repoPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	completions := make([]cobra.Completion, len(branches))
+	for i, b := range branches {
+		completions[i] = b.Ref()
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeLocalBranches completes local branches, for the base branch.
+func completeLocalBranches(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	_, service, repoPath, err := completionRepo()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	branches, err := service.ListBranches(repoPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var completions []cobra.Completion
+	for _, b := range branches {
+		if b.Remote == "" {
+			completions = append(completions, b.Name)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRemovals completes the worktrees in the removal journal, most
+// recently removed first.
+func completeRemovals(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	manager, _, repoPath, err := completionRepo()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	entries, err := manager.ListRemovals(repoPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var names []string
+	var completions []cobra.Completion
+	for _, entry := range entries {
+		if slices.Contains(names, entry.Name) {
+			continue
+		}
+		names = append(names, entry.Name)
+		completions = append(completions, cobra.CompletionWithDesc(entry.Name,
+			fmt.Sprintf("removed %s", entry.Removed.Local().Format("2006-01-02 15:04"))))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// worktreeCompletions returns the names of worktrees described by their
+// branch and status, leaving out the names in exclude.
+func worktreeCompletions(worktrees []internal.Worktree, exclude []string) []cobra.Completion {
+	var completions []cobra.Completion
+	for _, wt := range worktrees {
+		if slices.Contains(exclude, wt.Name()) {
+			continue
+		}
+		branch := wt.Branch
+		if wt.Detached() {
+			branch = "detached at " + internal.ShortCommit(wt.Head)
+		}
+		completions = append(completions, cobra.CompletionWithDesc(wt.Name(), branch+", "+formatStatus(wt.Status)))
+	}
+	return completions
+}
+
+// branchCompletions returns the local branches without a worktree, then the
+// remote-tracking ones without a local branch, by the name wt add creates
+// the local branch under. A name on several remotes is left out, since it
+// does not say which to track.
+func branchCompletions(branches []internal.Branch, worktrees []internal.Worktree) []cobra.Completion {
+	checkedOut := func(name string) bool {
+		return slices.ContainsFunc(worktrees, func(wt internal.Worktree) bool { return !wt.Detached() && wt.Branch == name })
+	}
+	var completions []cobra.Completion
+	for _, b := range branches {
+		if b.Remote == "" {
+			if !checkedOut(b.Name) {
+				completions = append(completions, cobra.CompletionWithDesc(b.Name, "local branch"))
+			}
+		} else if ref, ok := internal.TrackingStartPoint(branches, b.Name); ok {
+			completions = append(completions, cobra.CompletionWithDesc(b.Name, "tracking "+ref))
+		}
+	}
+	return completions
+}
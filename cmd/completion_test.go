@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/no-yan/wt/internal"
+	"github.com/spf13/cobra"
+)
+
+func TestWorktreeCompletions(t *testing.T) {
+	worktrees := []internal.Worktree{
+		{Path: "/repo", Branch: "main"},
+		{Path: "/repo/worktrees/feature-auth", Branch: "feature/auth", Status: internal.StatusDirty},
+		{Path: "/repo/worktrees/review", Branch: internal.DetachedBranch, Head: "3f2a9c1d8e7b"},
+	}
+
+	got := worktreeCompletions(worktrees, []string{"main"})
+	want := []cobra.Completion{
+		"feature-auth\tfeature/auth, dirty",
+		"review\tdetached at 3f2a9c1, clean",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("worktreeCompletions() = %q, want %q", got, want)
+	}
+}
+
+func TestBranchCompletions(t *testing.T) {
+	branches := []internal.Branch{
+		{Name: "feature/auth"},
+		{Name: "main"},
+		{Name: "fix/login", Remote: "origin"},
+		{Name: "main", Remote: "origin"},
+		{Name: "shared", Remote: "origin"},
+		{Name: "shared", Remote: "upstream"},
+	}
+	worktrees := []internal.Worktree{{Path: "/repo", Branch: "main"}}
+
+	got := branchCompletions(branches, worktrees)
+	want := []cobra.Completion{
+		"feature/auth\tlocal branch",
+		"fix/login\ttracking origin/fix/login",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("branchCompletions() = %q, want %q", got, want)
+	}
+}
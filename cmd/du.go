@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"github.com/no-yan/wt/internal"
+	"github.com/spf13/cobra"
+)
+
+var duCmd = &cobra.Command{
+	Use:   "du [<name>...]",
+	Short: "Show disk usage per worktree",
+	Long: `Show how much disk space each worktree uses, largest first.
+
+The size of each worktree is split into files tracked by git, untracked files
+and ignored files such as build output and node_modules. The .git directory
+and worktrees nested in another one are not counted towards it. Directories
+are walked concurrently.
+
+The sizes are cached, so 'wt list --size' can show them without measuring
+again; wt.sizeCacheTTL sets how long they are reused (default 1h).`,
+	ValidArgsFunction: completeWorktrees,
+	Run: func(cmd *cobra.Command, args []string) {
+		runner := internal.NewExecCommandRunner()
+		service := internal.NewGitService(runner)
+		manager := internal.NewWorktreeManager(service, runner)
+
+		repoPath, err := getRepoRoot(runner)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error finding git repository: %v\n", err)
+			os.Exit(1)
+		}
+
+		usages, err := manager.MeasureDiskUsage(repoPath, args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error measuring worktrees: %v\n", err)
+			os.Exit(1)
+		}
+
+		formatDiskUsage(usages, os.Stdout)
+	},
+}
+
+func formatDiskUsage(usages []internal.DiskUsage, w io.Writer) {
+	nameWidth := utf8.RuneCountInString("NAME")
+	for _, usage := range usages {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(usage.Name))
+	}
+
+	var total internal.DiskUsage
+	rows := [][]string{{"NAME", "TOTAL", "TRACKED", "UNTRACKED", "IGNORED"}}
+	for _, usage := range usages {
+		rows = append(rows, diskUsageRow(usage.Name, usage))
+		total.Tracked += usage.Tracked
+		total.Untracked += usage.Untracked
+		total.Ignored += usage.Ignored
+	}
+	if len(usages) > 1 {
+		rows = append(rows, diskUsageRow("total", total))
+	}
+
+	for _, row := range rows {
+		if _, err := fmt.Fprintf(w, "%-*s  %9s  %9s  %9s  %9s\n", nameWidth, row[0], row[1], row[2], row[3], row[4]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		}
+	}
+}
+
+func diskUsageRow(name string, usage internal.DiskUsage) []string {
+	return []string{name, formatSize(usage.Total()), formatSize(usage.Tracked), formatSize(usage.Untracked), formatSize(usage.Ignored)}
+}
+
+// formatSize renders a byte count with a binary unit, such as "1.5 GiB".
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	value := float64(bytes)
+	suffixes := []string{"KiB", "MiB", "GiB", "TiB", "PiB"}
+	i := -1
+	for value >= unit && i < len(suffixes)-1 {
+		value /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", value, suffixes[i])
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/no-yan/wt/internal"
+)
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{5 << 20, "5.0 MiB"},
+		{3 << 30, "3.0 GiB"},
+	}
+
+	for _, tt := range tests {
+		if got := formatSize(tt.bytes); got != tt.want {
+			t.Errorf("formatSize(%d) = %q, want %q", tt.bytes, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDiskUsage(t *testing.T) {
+	usages := []internal.DiskUsage{
+		{Name: "feature-auth", Tracked: 2048, Ignored: 3 << 20},
+		{Name: "main", Tracked: 1024, Untracked: 10},
+	}
+
+	var buf bytes.Buffer
+	formatDiskUsage(usages, &buf)
+
+	want := "NAME              TOTAL    TRACKED  UNTRACKED    IGNORED\n" +
+		"feature-auth    3.0 MiB    2.0 KiB        0 B    3.0 MiB\n" +
+		"main            1.0 KiB    1.0 KiB       10 B        0 B\n" +
+		"total           3.0 MiB    3.0 KiB       10 B    3.0 MiB\n"
+	if got := buf.String(); got != want {
+		t.Errorf("formatDiskUsage() =\n%s\nwant\n%s", got, want)
+	}
+}
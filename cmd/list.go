@@ -14,6 +14,7 @@ var (
 	listDirtyOnly bool
 	listVerbose   bool
 	listNamesOnly bool
+	listSize      bool
 )
 
 var listCmd = &cobra.Command{
@@ -25,7 +26,9 @@ var listCmd = &cobra.Command{
 Filtering options:
   --dirty       Show only worktrees with uncommitted changes
   --verbose     Show detailed git status information
-  --names-only  Show only worktree names (useful for scripting)`,
+  --names-only  Show only worktree names (useful for scripting)
+  --size        Add each worktree's disk usage, cached as described in 'wt du'`,
+	ValidArgsFunction: cobra.NoFileCompletions,
 	Run: func(cmd *cobra.Command, args []string) {
 		runner := internal.NewExecCommandRunner()
 		service := internal.NewGitService(runner)
@@ -39,13 +42,22 @@ Filtering options:
 		// Apply filters
 		filtered := filterWorktrees(worktrees, listDirtyOnly)
 
+		var sizes map[string]internal.DiskUsage
+		if listSize && !listNamesOnly {
+			sizes, err = listSizes(runner, service)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error measuring worktrees: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
 		// Format output based on flags
 		if listNamesOnly {
 			formatWorktreeNames(filtered, os.Stdout)
 		} else if listVerbose {
-			formatWorktreeListVerbose(filtered, os.Stdout, service)
+			formatWorktreeListVerbose(filtered, os.Stdout, service, sizes)
 		} else {
-			formatWorktreeList(filtered, os.Stdout)
+			formatWorktreeList(filtered, os.Stdout, sizes)
 		}
 	},
 }
@@ -54,6 +66,17 @@ func init() {
 	listCmd.Flags().BoolVar(&listDirtyOnly, "dirty", false, "Show only worktrees with uncommitted changes")
 	listCmd.Flags().BoolVar(&listVerbose, "verbose", false, "Show detailed git status information")
 	listCmd.Flags().BoolVar(&listNamesOnly, "names-only", false, "Show only worktree names")
+	listCmd.Flags().BoolVar(&listSize, "size", false, "Show the disk usage of each worktree")
+}
+
+// listSizes returns the sizes of the worktrees by path, measuring only
+// those not in the cache.
+func listSizes(runner internal.CommandRunner, service *internal.GitService) (map[string]internal.DiskUsage, error) {
+	repoPath, err := getRepoRoot(runner)
+	if err != nil {
+		return nil, err
+	}
+	return internal.NewWorktreeManager(service, runner).CachedDiskUsage(repoPath)
 }
 
 func filterWorktrees(worktrees []internal.Worktree, dirtyOnly bool) []internal.Worktree {
@@ -70,11 +93,11 @@ func filterWorktrees(worktrees []internal.Worktree, dirtyOnly bool) []internal.W
 	return filtered
 }
 
-func formatWorktreeList(worktrees []internal.Worktree, w io.Writer) {
+func formatWorktreeList(worktrees []internal.Worktree, w io.Writer, sizes map[string]internal.DiskUsage) {
 	ws := calculateColumnWidths(worktrees)
 	for _, wt := range worktrees {
 		status := formatStatus(wt.Status)
-		if _, err := fmt.Fprintf(w, "%-*s  %-*s  (%s)\n", ws.name, wt.Name(), ws.path, wt.Path, status); err != nil {
+		if _, err := fmt.Fprintf(w, "%-*s  %-*s  (%s)%s\n", ws.name, wt.Name(), ws.path, wt.Path, status, formatSizeColumn(sizes, wt)); err != nil {
 			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
 		}
 	}
@@ -106,6 +129,19 @@ func calculateColumnWidths(worktrees []internal.Worktree) columnWidths {
 	return ws
 }
 
+// formatSizeColumn returns the size column for wt, or nothing when sizes
+// were not requested. Worktrees that could not be measured show "-".
+func formatSizeColumn(sizes map[string]internal.DiskUsage, wt internal.Worktree) string {
+	if sizes == nil {
+		return ""
+	}
+	size := "-"
+	if usage, ok := sizes[wt.Path]; ok {
+		size = formatSize(usage.Total())
+	}
+	return fmt.Sprintf("  %9s", size)
+}
+
 // formatStatus converts a worktree status to its string representation
 func formatStatus(status internal.Status) string {
 	switch status {
@@ -118,11 +154,11 @@ func formatStatus(status internal.Status) string {
 	}
 }
 
-func formatWorktreeListVerbose(worktrees []internal.Worktree, w io.Writer, service *internal.GitService) {
+func formatWorktreeListVerbose(worktrees []internal.Worktree, w io.Writer, service *internal.GitService, sizes map[string]internal.DiskUsage) {
 	ws := calculateColumnWidths(worktrees)
 	for _, wt := range worktrees {
 		status := formatStatus(wt.Status)
-		if _, err := fmt.Fprintf(w, "%-*s  %-*s  %-*s  (%s)\n", ws.name, wt.Name(), ws.branch, wt.Branch, ws.path, wt.Path, status); err != nil {
+		if _, err := fmt.Fprintf(w, "%-*s  %-*s  %-*s  (%s)%s\n", ws.name, wt.Name(), ws.branch, wt.Branch, ws.path, wt.Path, status, formatSizeColumn(sizes, wt)); err != nil {
 			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
 		}
 
@@ -48,7 +48,7 @@ func TestListCommand(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var output bytes.Buffer
-			formatWorktreeList(tt.worktrees, &output)
+			formatWorktreeList(tt.worktrees, &output, nil)
 
 			if got := output.String(); got != tt.want {
 				t.Errorf("formatWorktreeList() = %q, want %q", got, tt.want)
@@ -59,7 +59,7 @@ func TestListCommand(t *testing.T) {
 
 func TestFormatWorktreeList_EmptySlice(t *testing.T) {
 	var buf bytes.Buffer
-	formatWorktreeList([]internal.Worktree{}, &buf)
+	formatWorktreeList([]internal.Worktree{}, &buf, nil)
 	
 	output := buf.String()
 	if output != "" {
@@ -90,7 +90,7 @@ func TestFormatWorktreeList_AlignedOutput(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	formatWorktreeList(worktrees, &buf)
+	formatWorktreeList(worktrees, &buf, nil)
 
 	output := buf.String()
 	
@@ -130,7 +130,7 @@ func TestFormatWorktreeListVerbose_AlignedOutput(t *testing.T) {
 
 	var buf bytes.Buffer
 	// Pass nil for service as we won't trigger detailed status in this test
-	formatWorktreeListVerbose(worktrees, &buf, nil)
+	formatWorktreeListVerbose(worktrees, &buf, nil, nil)
 
 	output := buf.String()
 
@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/no-yan/wt/internal"
+)
+
+// pickerRows is the number of worktrees the picker shows at once.
+const pickerRows = 10
+
+var errPickerCancelled = errors.New("no worktree selected")
+
+// Keys the picker reacts to besides typed characters.
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyEnter     = "enter"
+	keyBackspace = "backspace"
+	keyClear     = "clear"
+	keyCancel    = "cancel"
+)
+
+// canPick reports whether a picker can be shown: stdin must be a terminal,
+// while stdout is usually captured by the shell function.
+func canPick() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return false
+	}
+	_ = tty.Close()
+	return true
+}
+
+// pickWorktree lets the user choose one of worktrees on the terminal,
+// filtering them as they type. It draws on and reads from /dev/tty so the
+// chosen path can still be printed to stdout.
+func pickWorktree(worktrees []internal.Worktree) (internal.Worktree, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return internal.Worktree{}, fmt.Errorf("failed to open terminal: %w", err)
+	}
+	defer func() { _ = tty.Close() }()
+
+	restore, err := rawMode(tty)
+	if err != nil {
+		return internal.Worktree{}, fmt.Errorf("failed to set up terminal: %w", err)
+	}
+	defer restore()
+
+	return newPicker(worktrees).run(tty, tty)
+}
+
+// rawMode makes tty deliver keys as they are pressed, without echoing
+// them, and returns a function restoring the previous settings.
+func rawMode(tty *os.File) (func(), error) {
+	state, err := stty(tty, "-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty(tty, "-icanon", "-echo", "-isig", "min", "1", "time", "0"); err != nil {
+		return nil, err
+	}
+	return func() { _, _ = stty(tty, strings.TrimSpace(state)) }, nil
+}
+
+func stty(tty *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = tty
+	output, err := cmd.Output()
+	return string(output), err
+}
+
+// picker is the state of the worktree picker.
+type picker struct {
+	worktrees []internal.Worktree
+	query     []rune
+	matches   []internal.Worktree
+	selected  int
+}
+
+func newPicker(worktrees []internal.Worktree) *picker {
+	p := &picker{worktrees: worktrees}
+	p.filter()
+	return p
+}
+
+// filter shows the worktrees matching the query, best first, or all of
+// them in order when nothing has been typed.
+func (p *picker) filter() {
+	p.selected = 0
+	if len(p.query) == 0 {
+		p.matches = p.worktrees
+		return
+	}
+	p.matches = nil
+	for _, match := range internal.MatchWorktrees(p.worktrees, string(p.query)) {
+		p.matches = append(p.matches, match.Worktree)
+	}
+}
+
+// handle applies key and reports whether the picker is done. The chosen
+// worktree is only set when one was selected.
+func (p *picker) handle(key string) (chosen *internal.Worktree, done bool) {
+	switch key {
+	case keyUp:
+		if p.selected > 0 {
+			p.selected--
+		}
+	case keyDown:
+		if p.selected < len(p.matches)-1 {
+			p.selected++
+		}
+	case keyEnter:
+		if len(p.matches) > 0 {
+			return &p.matches[p.selected], true
+		}
+	case keyBackspace:
+		if len(p.query) > 0 {
+			p.query = p.query[:len(p.query)-1]
+			p.filter()
+		}
+	case keyClear:
+		p.query = nil
+		p.filter()
+	case keyCancel:
+		return nil, true
+	default:
+		p.query = append(p.query, []rune(key)...)
+		p.filter()
+	}
+	return nil, false
+}
+
+// run reads keys from in and draws on out until a worktree is chosen or
+// the picker is cancelled.
+func (p *picker) run(in io.Reader, out io.Writer) (internal.Worktree, error) {
+	buf := make([]byte, 64)
+	p.render(out)
+	for {
+		n, err := in.Read(buf)
+		for _, key := range parseKeys(buf[:n]) {
+			chosen, done := p.handle(key)
+			if !done {
+				continue
+			}
+			clearPicker(out)
+			if chosen == nil {
+				return internal.Worktree{}, errPickerCancelled
+			}
+			return *chosen, nil
+		}
+		if err != nil {
+			clearPicker(out)
+			if err == io.EOF {
+				return internal.Worktree{}, errPickerCancelled
+			}
+			return internal.Worktree{}, err
+		}
+		p.render(out)
+	}
+}
+
+// render draws the query and the visible matches below the cursor, then
+// puts the cursor back at the end of the query.
+func (p *picker) render(out io.Writer) {
+	var b strings.Builder
+	b.WriteString("\r\x1b[J> " + string(p.query))
+
+	start := max(0, p.selected-pickerRows+1)
+	end := min(len(p.matches), start+pickerRows)
+	nameWidth := 0
+	for _, wt := range p.matches[start:end] {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(wt.Name()))
+	}
+	for i := start; i < end; i++ {
+		marker := " "
+		if i == p.selected {
+			marker = ">"
+		}
+		wt := p.matches[i]
+		fmt.Fprintf(&b, "\r\n%s %-*s  %s", marker, nameWidth, wt.Name(), wt.Branch)
+	}
+	if end == start {
+		b.WriteString("\r\n  no matching worktrees")
+		end++
+	}
+
+	fmt.Fprintf(&b, "\x1b[%dA\r\x1b[%dC", end-start, 2+len(p.query))
+	_, _ = io.WriteString(out, b.String())
+}
+
+func clearPicker(out io.Writer) {
+	_, _ = io.WriteString(out, "\r\x1b[J")
+}
+
+// parseKeys splits the bytes read from the terminal into keys. Arrow keys
+// arrive as escape sequences, with or without modifiers, and other
+// sequences are skipped whole; a lone escape cancels.
+func parseKeys(input []byte) []string {
+	var keys []string
+	for len(input) > 0 {
+		if input[0] == 0x1b {
+			if n := escapeSequenceLen(input); n > 0 {
+				switch input[n-1] {
+				case 'A':
+					keys = append(keys, keyUp)
+				case 'B':
+					keys = append(keys, keyDown)
+				}
+				input = input[n:]
+				continue
+			}
+			keys = append(keys, keyCancel)
+			input = input[1:]
+			continue
+		}
+
+		r, size := utf8.DecodeRune(input)
+		input = input[size:]
+		switch r {
+		case '\r', '\n':
+			keys = append(keys, keyEnter)
+		case 0x7f, 0x08:
+			keys = append(keys, keyBackspace)
+		case 0x15: // Ctrl-U
+			keys = append(keys, keyClear)
+		case 0x10, 0x0b: // Ctrl-P, Ctrl-K
+			keys = append(keys, keyUp)
+		case 0x0e: // Ctrl-N
+			keys = append(keys, keyDown)
+		case 0x03, 0x04: // Ctrl-C, Ctrl-D
+			keys = append(keys, keyCancel)
+		default:
+			if unicode.IsPrint(r) {
+				keys = append(keys, string(r))
+			}
+		}
+	}
+	return keys
+}
+
+// escapeSequenceLen returns the length of the CSI (ESC [) or SS3 (ESC O)
+// sequence at the start of input, or 0 if there is none. A CSI sequence
+// runs up to its final byte, 0x40-0x7E, after any parameters such as the
+// "1;5" of Ctrl-Up; a truncated one takes the rest of the input.
+func escapeSequenceLen(input []byte) int {
+	if len(input) < 3 {
+		return 0
+	}
+	switch input[1] {
+	case 'O':
+		return 3
+	case '[':
+		for i := 2; i < len(input); i++ {
+			if input[i] >= 0x40 && input[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(input)
+	}
+	return 0
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/no-yan/wt/internal"
+)
+
+func TestParseKeys(t *testing.T) {
+	got := parseKeys([]byte("ab\x1b[A\x1b[B\x7f\x15\x0e\x10\r\x1b"))
+	want := []string{"a", "b", keyUp, keyDown, keyBackspace, keyClear, keyDown, keyUp, keyEnter, keyCancel}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseKeys() = %q, want %q", got, want)
+	}
+
+	// Modified arrows and other keys arrive as longer sequences
+	got = parseKeys([]byte("a\x1b[1;5A\x1b[3~b\x1bOB\x1b[200~c"))
+	want = []string{"a", keyUp, "b", keyDown, "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseKeys() = %q, want %q", got, want)
+	}
+}
+
+func TestPicker_Run(t *testing.T) {
+	worktrees := []internal.Worktree{
+		{Path: "/repo", Branch: "main"},
+		{Path: "/repo/worktrees/feature-auth", Branch: "feature/auth"},
+		{Path: "/repo/worktrees/hotfix-bug-123", Branch: "hotfix/bug-123"},
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{name: "enter picks the first worktree", input: "\r", want: "/repo"},
+		{name: "arrow keys move the selection", input: "\x1b[B\x1b[B\x1b[A\r", want: "/repo/worktrees/feature-auth"},
+		{name: "typing filters", input: "hot\r", want: "/repo/worktrees/hotfix-bug-123"},
+		{name: "backspace widens the filter", input: "hotx\x7f\r", want: "/repo/worktrees/hotfix-bug-123"},
+		{name: "enter without matches does nothing", input: "zzz\r\x1b", wantErr: errPickerCancelled},
+		{name: "escape cancels", input: "\x1b", wantErr: errPickerCancelled},
+		{name: "end of input cancels", input: "fea", wantErr: errPickerCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			got, err := newPicker(worktrees).run(strings.NewReader(tt.input), &out)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("run() error = %v, want %v", err, tt.wantErr)
+			}
+			if got.Path != tt.want {
+				t.Errorf("run() = %q, want %q", got.Path, tt.want)
+			}
+		})
+	}
+}
+
+func TestPicker_Render(t *testing.T) {
+	p := newPicker([]internal.Worktree{
+		{Path: "/repo", Branch: "main"},
+		{Path: "/repo/worktrees/feature-auth", Branch: "feature/auth"},
+	})
+	p.handle(keyDown)
+
+	var out bytes.Buffer
+	p.render(&out)
+
+	want := "\r\x1b[J> " +
+		"\r\n  main          main" +
+		"\r\n> feature-auth  feature/auth" +
+		"\x1b[2A\r\x1b[2C"
+	if got := out.String(); got != want {
+		t.Errorf("render() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/no-yan/wt/internal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	promptFormat   string
+	promptNoStatus bool
+	promptTimeout  time.Duration
+	promptCache    time.Duration
+)
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print the current worktree for a shell prompt",
+	Long: `Print the name, branch and status markers of the worktree containing the
+current directory, for use in a shell prompt. Nothing is printed outside a
+worktree.
+
+The worktree is found by reading the git files above the current directory,
+without running git or looking at other worktrees; its name follows
+wt.pathTemplate as in 'wt list'. The status then takes one 'git status',
+which is stopped after --timeout so a slow repository never holds up the
+prompt; the markers are simply left out. --cache reuses a status for the given
+time as long as HEAD and the index are unchanged.
+
+The format is a Go template with the fields .Name, .Branch, .Path, .Main,
+.Dirty, .Ahead and .Behind, and .Markers for "*" (uncommitted changes),
+"↑N" and "↓N" (commits ahead of and behind the upstream). The default is
+  ` + internal.DefaultPromptFormat + `
+
+'wt shell-init' defines a wt_prompt function calling this command.`,
+	Args:              cobra.NoArgs,
+	ValidArgsFunction: cobra.NoFileCompletions,
+	Run: func(cmd *cobra.Command, args []string) {
+		tmpl, err := template.New("prompt").Parse(promptFormat)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid format: %v\n", err)
+			os.Exit(1)
+		}
+
+		runner := internal.NewExecCommandRunner()
+		manager := internal.NewWorktreeManager(internal.NewGitService(runner), runner)
+
+		dir, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		info, err := manager.Prompt(dir, internal.PromptOptions{
+			Status:   !promptNoStatus,
+			Timeout:  promptTimeout,
+			CacheTTL: promptCache,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := formatPrompt(tmpl, info, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	promptCmd.Flags().StringVar(&promptFormat, "format", internal.DefaultPromptFormat, "Go template for the output")
+	promptCmd.Flags().BoolVar(&promptNoStatus, "no-status", false, "Leave out the status, so git is not run")
+	promptCmd.Flags().DurationVar(&promptTimeout, "timeout", internal.DefaultPromptTimeout, "Give up on the status after this long")
+	promptCmd.Flags().DurationVar(&promptCache, "cache", 0, "Reuse a status read within this long, e.g. 2s")
+}
+
+// formatPrompt writes info with tmpl, without a trailing newline so it can
+// be embedded in a prompt. A nil info writes nothing.
+func formatPrompt(tmpl *template.Template, info *internal.PromptInfo, w io.Writer) error {
+	if info == nil {
+		return nil
+	}
+	return tmpl.Execute(w, info)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/no-yan/wt/internal"
+)
+
+func TestFormatPrompt(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		info   *internal.PromptInfo
+		want   string
+	}{
+		{
+			name:   "name matching the branch",
+			format: internal.DefaultPromptFormat,
+			info:   &internal.PromptInfo{Name: "main", Branch: "main"},
+			want:   "main",
+		},
+		{
+			name:   "branch and markers",
+			format: internal.DefaultPromptFormat,
+			info:   &internal.PromptInfo{Name: "feature-auth", Branch: "feature/auth", HasStatus: true, Dirty: true, Ahead: 2},
+			want:   "feature-auth (feature/auth) *↑2",
+		},
+		{
+			name:   "custom format",
+			format: "[{{.Branch}}{{if .Dirty}}!{{end}}]",
+			info:   &internal.PromptInfo{Name: "fix", Branch: "fix/login", Dirty: true},
+			want:   "[fix/login!]",
+		},
+		{
+			name:   "outside a worktree",
+			format: internal.DefaultPromptFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			if err := formatPrompt(template.Must(template.New("prompt").Parse(tt.format)), tt.info, &out); err != nil {
+				t.Fatalf("formatPrompt() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("formatPrompt() = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/no-yan/wt/internal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	recentAll       bool
+	recentNamesOnly bool
+)
+
+var recentCmd = &cobra.Command{
+	Use:   "recent",
+	Short: "List recently used worktrees",
+	Long: `List the worktrees most recently switched to or from, most recent first.
+
+The current worktree is left out, and the number in front of each worktree
+is the N to pass to 'wt switch -N'. The history is kept per repository and
+shared by every shell.
+
+Use --all to list the worktrees never switched to as well, after the others,
+and --names-only to print only the names (useful for scripting).`,
+	Args:              cobra.NoArgs,
+	ValidArgsFunction: cobra.NoFileCompletions,
+	Run: func(cmd *cobra.Command, args []string) {
+		runner := internal.NewExecCommandRunner()
+		service := internal.NewGitService(runner)
+		manager := internal.NewWorktreeManager(service, runner)
+
+		repoPath, err := getRepoRoot(runner)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error finding git repository: %v\n", err)
+			os.Exit(1)
+		}
+
+		worktrees, err := service.ListWorktrees()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing worktrees: %v\n", err)
+			os.Exit(1)
+		}
+		history, err := manager.History(repoPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading switch history: %v\n", err)
+			os.Exit(1)
+		}
+
+		var current string
+		if dir, err := os.Getwd(); err == nil {
+			// git reports worktree paths with symlinks resolved
+			if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+				dir = resolved
+			}
+			if wt, ok := internal.FindWorktreeByPath(worktrees, dir); ok {
+				current = wt.Path
+			}
+		}
+
+		recent := internal.RecentWorktrees(worktrees, history, current, recentAll)
+		if recentNamesOnly {
+			for _, wt := range recent {
+				fmt.Println(wt.Name())
+			}
+			return
+		}
+		if len(recent) == 0 {
+			fmt.Println("No recently used worktrees.")
+			return
+		}
+		formatRecentWorktrees(recent, os.Stdout)
+	},
+}
+
+func init() {
+	recentCmd.Flags().BoolVar(&recentAll, "all", false, "Also list worktrees never switched to")
+	recentCmd.Flags().BoolVar(&recentNamesOnly, "names-only", false, "Show only worktree names")
+}
+
+func formatRecentWorktrees(recent []internal.RecentWorktree, w io.Writer) {
+	var nameWidth, branchWidth int
+	for _, wt := range recent {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(wt.Name()))
+		branchWidth = max(branchWidth, utf8.RuneCountInString(wt.Branch))
+	}
+	for i, wt := range recent {
+		index, used := fmt.Sprintf("-%d", i+1), "never"
+		if wt.Used.IsZero() {
+			index = ""
+		} else {
+			used = wt.Used.Local().Format("2006-01-02 15:04")
+		}
+		if _, err := fmt.Fprintf(w, "%3s  %-*s  %-*s  %s\n", index, nameWidth, wt.Name(), branchWidth, wt.Branch, used); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		}
+	}
+}
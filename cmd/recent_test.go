@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/no-yan/wt/internal"
+)
+
+func TestSwitchHistoryArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"switch", "-"}, []string{"switch", "@-"}},
+		{[]string{"sw", "-2"}, []string{"sw", "@-2"}},
+		{[]string{"switch", "feature"}, []string{"switch", "feature"}},
+		{[]string{"switch", "--", "-2"}, []string{"switch", "--", "-2"}},
+		{[]string{"remove", "-1"}, []string{"remove", "-1"}},
+		{[]string{"switch"}, []string{"switch"}},
+	}
+
+	for _, tt := range tests {
+		if got := switchHistoryArgs(tt.args); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("switchHistoryArgs(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestFormatRecentWorktrees(t *testing.T) {
+	used := time.Date(2025, 1, 1, 12, 0, 0, 0, time.Local)
+	recent := []internal.RecentWorktree{
+		{Worktree: internal.Worktree{Path: "/repo/worktrees/feature-auth", Branch: "feature/auth"}, Used: used},
+		{Worktree: internal.Worktree{Path: "/repo", Branch: "main"}, Used: used},
+		{Worktree: internal.Worktree{Path: "/repo/worktrees/spike", Branch: "spike"}},
+	}
+
+	var buf bytes.Buffer
+	formatRecentWorktrees(recent, &buf)
+
+	want := " -1  feature-auth  feature/auth  2025-01-01 12:00\n" +
+		" -2  main          main          2025-01-01 12:00\n" +
+		"     spike         spike         never\n"
+	if got := buf.String(); got != want {
+		t.Errorf("formatRecentWorktrees() =\n%q\nwant\n%q", got, want)
+	}
+}
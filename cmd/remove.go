@@ -3,23 +3,82 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/no-yan/wt/internal"
 	"github.com/spf13/cobra"
 )
 
+var (
+	removeForce             bool
+	removeDeleteBranch      bool
+	removeForceDeleteBranch bool
+	removeKeepGoing         bool
+
+	removeMerged    bool
+	removeBranch    string
+	removeOlderThan string
+	removeCleanOnly bool
+	removeYes       bool
+
+	removeCurrent bool
+	removeDryRun  bool
+	removeArchive bool
+)
+
 var removeCmd = &cobra.Command{
-	Use:     "remove <name>...",
+	Use:     "remove [<name>|<pattern>]...",
 	Aliases: []string{"rm"},
 	Short:   "Remove one or more worktrees",
 	Long: `Remove one or more worktrees by name.
 
+A worktree can be given by its name, its branch, a path in it or a unique
+prefix of either. '@' is the current worktree and '@-' the previous one.
+
 Safety checks:
 - Cannot remove the main worktree
-- Cannot remove worktrees with uncommitted changes
-- Must be in worktrees/ subdirectory
-- When removing multiple worktrees, validates all before removing any (fail-fast)`,
-	Args: cobra.MinimumNArgs(1),
+- Cannot remove worktrees with uncommitted changes, unless --force is given
+- Cannot remove worktrees whose HEAD has commits missing from its upstream
+  (or the base branch if it has none), unless --archive is given
+- Must be inside the managed worktree directory (worktrees/ by default)
+- When removing multiple worktrees, validates all before removing any (fail-fast)
+- If a removal still fails, worktrees already removed are added back at the
+  same path and branch; use --keep-going to remove what can be removed instead
+
+With --force, uncommitted changes (including untracked files) are first saved
+under refs/wt/backups/<name>. Nothing is removed if the backup fails. Recover
+the changes in any worktree with:
+
+  git stash apply refs/wt/backups/<name>
+
+With --archive, unpushed commits are written to a git bundle with a JSON
+description next to it, in wt.archiveDir or .git/wt/archive by default.
+
+With --delete-branch, the branch of each worktree is deleted as well, but only
+if it is merged into the base branch (wt.baseBranch, or the branch checked out
+in the main worktree). --force-delete-branch deletes unmerged branches too.
+Branches are checked together with the worktrees, so nothing is removed if a
+branch would be kept.
+
+Worktrees can also be selected by glob pattern (quote it so the shell leaves
+it alone) and by filters, which combine with each other and with the names:
+
+  wt remove 'review-*'
+  wt remove --branch 'spike/*' --older-than 14d
+  wt remove --merged --clean-only
+
+The expanded list is shown for confirmation before the usual validation;
+--yes skips the prompt.
+
+'.', '@' or --current selects the worktree containing the current directory.
+With the shell integration from 'wt shell-init', the shell first moves to the
+main worktree (in a bare repository, another worktree or the repository root)
+so it is not left in a deleted directory.
+
+--dry-run validates the selection and prints the path of each worktree that
+would be removed, one per line, without removing anything.`,
+	Args:              removeArgs,
+	ValidArgsFunction: completeRemoveTargets,
 	Run: func(cmd *cobra.Command, args []string) {
 		runner := internal.NewExecCommandRunner()
 		gitService := internal.NewGitService(runner)
@@ -31,22 +90,169 @@ Safety checks:
 			os.Exit(1)
 		}
 
-		if len(args) == 1 {
-			// Single worktree removal
-			if err := manager.RemoveWorktree(repoPath, args[0]); err != nil {
-				fmt.Fprintf(os.Stderr, "Error removing worktree: %v\n", err)
+		args, err = resolveRemoveTargets(manager, gitService, repoPath, args, removeCurrent)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		filter := internal.WorktreeFilter{
+			Patterns:  args,
+			Branch:    removeBranch,
+			Merged:    removeMerged,
+			CleanOnly: removeCleanOnly,
+		}
+		if removeOlderThan != "" {
+			if filter.OlderThan, err = internal.ParseAge(removeOlderThan); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
-			fmt.Printf("Removed worktree: %s\n", args[0])
-		} else {
-			// Multiple worktree removal with fail-fast validation
-			if err := manager.RemoveMultipleWorktrees(repoPath, args); err != nil {
-				fmt.Fprintf(os.Stderr, "Error removing worktrees: %v\n", err)
+		}
+
+		names := args
+		if filter.Expands() {
+			if names, err = manager.SelectWorktrees(repoPath, filter); err != nil {
+				fmt.Fprintf(os.Stderr, "Error selecting worktrees: %v\n", err)
 				os.Exit(1)
 			}
-			for _, name := range args {
-				fmt.Printf("Removed worktree: %s\n", name)
+			if len(names) == 0 {
+				fmt.Println("No worktrees match.")
+				return
+			}
+			if !removeDryRun && !confirmRemoval(names) {
+				fmt.Println("Removal cancelled.")
+				return
+			}
+		}
+
+		// Multiple worktrees are validated together before any is removed
+		results, err := manager.RemoveWorktrees(repoPath, names, internal.RemoveOptions{
+			Force:             removeForce,
+			DeleteBranch:      removeDeleteBranch,
+			ForceDeleteBranch: removeForceDeleteBranch,
+			KeepGoing:         removeKeepGoing,
+			DryRun:            removeDryRun,
+			Archive:           removeArchive,
+		})
+		if removeDryRun {
+			for _, result := range results {
+				if result.Err == nil {
+					fmt.Println(result.Path)
+				}
+			}
+		}
+		for _, result := range results {
+			printRemoveResult(result)
+		}
+		if err != nil {
+			if removeKeepGoing {
+				for _, result := range results {
+					if result.Err != nil {
+						fmt.Fprintf(os.Stderr, "Failed %s: %v\n", result.Name, result.Err)
+					}
+				}
 			}
+			fmt.Fprintf(os.Stderr, "Error removing worktree: %v\n", err)
+			os.Exit(1)
 		}
 	},
 }
+
+// removeArgs requires a worktree name unless a filter selects them.
+func removeArgs(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return nil
+	}
+	for _, flag := range []string{"merged", "branch", "older-than", "clean-only", "current"} {
+		if cmd.Flags().Changed(flag) {
+			return nil
+		}
+	}
+	return fmt.Errorf("requires at least 1 worktree name, pattern or filter")
+}
+
+// resolveRemoveTargets replaces each target in args that is not a glob with
+// the name of the worktree it refers to, and appends the current worktree if
+// current is set. Fuzzy matching is left out so a typo never selects a
+// worktree to remove.
+func resolveRemoveTargets(manager *internal.WorktreeManager, service *internal.GitService, repoPath string, args []string, current bool) ([]string, error) {
+	if current {
+		args = append(args, internal.AliasCurrent)
+	}
+	if !slices.ContainsFunc(args, func(arg string) bool { return !internal.IsGlob(arg) }) {
+		return args, nil
+	}
+
+	worktrees, err := service.ListWorktrees()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	resolver := newResolver(manager, repoPath, worktrees, false)
+
+	resolved := make([]string, 0, len(args))
+	for _, arg := range args {
+		if !internal.IsGlob(arg) {
+			wt, err := resolver.Resolve(arg)
+			if err != nil {
+				return nil, err
+			}
+			arg = wt.Name()
+		}
+		if !slices.Contains(resolved, arg) {
+			resolved = append(resolved, arg)
+		}
+	}
+	return resolved, nil
+}
+
+func confirmRemoval(names []string) bool {
+	fmt.Printf("The following %d worktree(s) will be removed:\n", len(names))
+	for _, name := range names {
+		fmt.Printf("  %s\n", name)
+	}
+	if removeYes {
+		return true
+	}
+
+	fmt.Print("\nProceed? (y/N): ")
+	var response string
+	_, _ = fmt.Scanln(&response)
+	return response == "y" || response == "Y"
+}
+
+func printRemoveResult(result internal.RemoveResult) {
+	if !result.Removed {
+		return
+	}
+	fmt.Printf("Removed worktree: %s\n", result.Name)
+	if result.BackupRef != "" {
+		fmt.Printf("  Uncommitted changes saved to %s\n", result.BackupRef)
+		fmt.Printf("  Recover them with: git stash apply %s\n", result.BackupRef)
+	}
+	if result.ArchivePath != "" {
+		fmt.Printf("  Unpushed commits archived to %s\n", result.ArchivePath)
+		if result.Branch != "" {
+			fmt.Printf("  Recover them with: git fetch %s HEAD:%s\n", result.ArchivePath, result.Branch)
+		} else {
+			fmt.Printf("  Recover them with: git fetch %s HEAD\n", result.ArchivePath)
+		}
+	}
+	if result.BranchDeleted {
+		fmt.Printf("Deleted branch: %s\n", result.Branch)
+	}
+}
+
+func init() {
+	removeCmd.Flags().BoolVarP(&removeForce, "force", "f", false, "Back up uncommitted changes and remove dirty worktrees")
+	removeCmd.Flags().BoolVarP(&removeDeleteBranch, "delete-branch", "d", false, "Also delete the branch if it is merged into the base branch")
+	removeCmd.Flags().BoolVarP(&removeForceDeleteBranch, "force-delete-branch", "D", false, "Also delete the branch even if it is not merged")
+	removeCmd.Flags().BoolVar(&removeKeepGoing, "keep-going", false, "Attempt every worktree and report failures instead of restoring on error")
+	removeCmd.Flags().BoolVar(&removeMerged, "merged", false, "Select worktrees merged into the base branch (not at its tip)")
+	removeCmd.Flags().StringVar(&removeBranch, "branch", "", "Select worktrees whose branch matches a glob, e.g. 'review/*'")
+	removeCmd.Flags().StringVar(&removeOlderThan, "older-than", "", "Select worktrees whose last commit is older than an age, e.g. 14d")
+	removeCmd.Flags().BoolVar(&removeCleanOnly, "clean-only", false, "Select only worktrees without uncommitted changes")
+	removeCmd.Flags().BoolVarP(&removeYes, "yes", "y", false, "Remove selected worktrees without confirmation")
+	removeCmd.Flags().BoolVar(&removeCurrent, "current", false, "Remove the worktree containing the current directory")
+	removeCmd.Flags().BoolVar(&removeDryRun, "dry-run", false, "Print the paths of the worktrees that would be removed")
+	removeCmd.Flags().BoolVar(&removeArchive, "archive", false, "Save unpushed commits to a git bundle and remove")
+}
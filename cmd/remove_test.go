@@ -2,6 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -357,4 +360,81 @@ func generateMockWorktreeOutput(worktrees []internal.Worktree) string {
 		parts = append(parts, part)
 	}
 	return strings.Join(parts, "\n\n")
-}
\ No newline at end of file
+}
+
+func TestRemoveCommand_FiltersReplaceNames(t *testing.T) {
+	cmd := &cobra.Command{Use: "remove", Args: removeArgs, Run: func(*cobra.Command, []string) {}}
+	cmd.Flags().Bool("merged", false, "")
+	cmd.Flags().String("branch", "", "")
+	cmd.Flags().String("older-than", "", "")
+	cmd.Flags().Bool("clean-only", false, "")
+
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err == nil {
+		t.Error("Expected error without names or filters")
+	}
+
+	cmd.SetArgs([]string{"--branch", "review/*"})
+	if err := cmd.Execute(); err != nil {
+		t.Errorf("Expected a filter to replace names, got: %v", err)
+	}
+}
+
+func TestResolveRemoveTargets(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	current := filepath.Join(root, "worktrees", "feature-auth")
+	if err := os.MkdirAll(filepath.Join(current, "src"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(filepath.Join(current, "src"))
+
+	mockRunner := &testMockCommandRunner{
+		outputs: map[string]string{
+			"git worktree list --porcelain": "worktree " + root + "\nHEAD abc123\nbranch refs/heads/main\n\n" +
+				"worktree " + current + "\nHEAD def456\nbranch refs/heads/feature/auth\n\n" +
+				"worktree " + root + "/worktrees/ui\nHEAD 789abc\nbranch refs/heads/ui",
+		},
+	}
+	service := internal.NewGitService(mockRunner)
+	manager := internal.NewWorktreeManager(service, mockRunner)
+
+	tests := []struct {
+		name    string
+		args    []string
+		current bool
+		want    []string
+		wantErr string
+	}{
+		{name: "dot", args: []string{"."}, want: []string{"feature-auth"}},
+		{name: "alias", args: []string{"@"}, want: []string{"feature-auth"}},
+		{name: "flag", args: nil, current: true, want: []string{"feature-auth"}},
+		{name: "dot with other names", args: []string{"ui", "."}, current: true, want: []string{"ui", "feature-auth"}},
+		{name: "untouched", args: []string{"ui"}, want: []string{"ui"}},
+		{name: "branch", args: []string{"feature/auth"}, want: []string{"feature-auth"}},
+		{name: "relative path", args: []string{"../../ui"}, want: []string{"ui"}},
+		{name: "prefix", args: []string{"feat"}, want: []string{"feature-auth"}},
+		{name: "globs are kept", args: []string{"review-*", "ui"}, want: []string{"review-*", "ui"}},
+		{name: "no fuzzy matching", args: []string{"fa"}, wantErr: `worktree "fa" not found`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveRemoveTargets(manager, service, root, tt.args, tt.current)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("resolveRemoveTargets() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveRemoveTargets() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveRemoveTargets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/no-yan/wt/internal"
+	"github.com/spf13/cobra"
+)
+
+var repairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Repair worktree links after moving the repository",
+	Long: `Repair the links between the repository and its worktrees.
+
+Each worktree has a .git file pointing at its administrative files in the
+repository's git directory, and those point back at the worktree. Moving or
+renaming the repository, or moving a worktree by hand, breaks these links and
+git then reports the worktrees as stale.
+
+wt repair finds worktrees with a broken link in either direction, including
+directories in the managed root whose .git file still names an old location,
+fixes them with git worktree repair and checks the links again. Worktrees that
+remain broken are reported; if their directory is gone, 'wt clean' can remove
+the entry.`,
+	Args:              cobra.NoArgs,
+	ValidArgsFunction: cobra.NoFileCompletions,
+	Run: func(cmd *cobra.Command, args []string) {
+		runner := internal.NewExecCommandRunner()
+		gitService := internal.NewGitService(runner)
+		manager := internal.NewWorktreeManager(gitService, runner)
+
+		repoPath, err := getRepoRoot(runner)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error finding git repository: %v\n", err)
+			os.Exit(1)
+		}
+
+		result, err := manager.RepairWorktrees(repoPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error repairing worktrees: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, path := range result.Repaired {
+			fmt.Printf("Repaired worktree: %s\n", path)
+		}
+		if len(result.Broken) > 0 {
+			fmt.Fprintf(os.Stderr, "Still broken:\n")
+			for _, broken := range result.Broken {
+				fmt.Fprintf(os.Stderr, "  %s\n", broken)
+			}
+			os.Exit(1)
+		}
+		if len(result.Repaired) == 0 {
+			fmt.Println("All worktree links are intact.")
+		}
+	},
+}
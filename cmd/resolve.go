@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/no-yan/wt/internal"
+)
+
+// newResolver returns a resolver for targets typed in the current
+// directory, with the switch history of the repository for @-. Without a
+// readable history, @- finds no previous worktree.
+func newResolver(manager *internal.WorktreeManager, repoPath string, worktrees []internal.Worktree, fuzzy bool) *internal.Resolver {
+	dir, _ := os.Getwd()
+	// git reports worktree paths with symlinks resolved
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	history, _ := manager.History(repoPath)
+	return &internal.Resolver{
+		Worktrees: worktrees,
+		Dir:       dir,
+		History:   history,
+		Fuzzy:     fuzzy,
+	}
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"github.com/no-yan/wt/internal"
+	"github.com/spf13/cobra"
+)
+
+var restoreList bool
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore [<name>]",
+	Short: "Restore a recently removed worktree",
+	Long: `Restore a worktree removed with 'wt remove'.
+
+Every removal is recorded in a journal in the git directory. Restoring adds the
+worktree back at its original path on the same branch, and removes it from the
+journal. Without a name the most recent removal is restored.
+
+- A deleted branch is created again at the recorded commit, which is fetched
+  from the archive of a removal with --archive if it no longer exists
+- A branch that still exists is checked out where it is now; if it moved on
+  since the removal, a warning names both commits
+- A detached worktree is checked out at the recorded commit
+- Changes backed up by a removal with --force are applied again
+- A locked worktree is locked again with the same reason
+
+Use --list to show the journal, most recent removal first. It keeps the last
+20 removals; set wt.journalLimit to change that (0 disables the journal). The
+backups and archives of older removals are deleted with their entries.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeRemovals,
+	Run: func(cmd *cobra.Command, args []string) {
+		runner := internal.NewExecCommandRunner()
+		gitService := internal.NewGitService(runner)
+		manager := internal.NewWorktreeManager(gitService, runner)
+
+		repoPath, err := getRepoRoot(runner)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error finding git repository: %v\n", err)
+			os.Exit(1)
+		}
+
+		if restoreList {
+			entries, err := manager.ListRemovals(repoPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading removals: %v\n", err)
+				os.Exit(1)
+			}
+			if len(entries) == 0 {
+				fmt.Println("No removed worktrees.")
+				return
+			}
+			formatRemovals(entries, os.Stdout)
+			return
+		}
+
+		name := ""
+		if len(args) == 1 {
+			name = args[0]
+		}
+		result, err := manager.Restore(repoPath, name)
+		if result != nil {
+			printRestoreResult(result)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error restoring worktree: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	restoreCmd.Flags().BoolVar(&restoreList, "list", false, "Show recently removed worktrees")
+}
+
+func printRestoreResult(result *internal.RestoreResult) {
+	fmt.Printf("Restored worktree: %s -> %s\n", result.Name, result.Path)
+	if result.BranchCreated {
+		fmt.Printf("  Recreated branch %s at %s\n", result.Branch, internal.ShortCommit(result.Head))
+	}
+	if result.BranchTip != "" {
+		fmt.Fprintf(os.Stderr, "Warning: branch %s moved since the removal, checked out at %s instead of %s\n",
+			result.Branch, internal.ShortCommit(result.BranchTip), internal.ShortCommit(result.Head))
+	}
+	if result.ChangesApplied {
+		fmt.Printf("  Applied uncommitted changes from %s\n", result.BackupRef)
+	}
+}
+
+func formatRemovals(entries []internal.RemovalEntry, w io.Writer) {
+	var nameWidth, branchWidth int
+	for _, entry := range entries {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(entry.Name))
+		branchWidth = max(branchWidth, utf8.RuneCountInString(removalBranch(entry)))
+	}
+	for _, entry := range entries {
+		if _, err := fmt.Fprintf(w, "%-*s  %-*s  %-7s  %s  %s\n",
+			nameWidth, entry.Name,
+			branchWidth, removalBranch(entry),
+			internal.ShortCommit(entry.Head),
+			entry.Removed.Local().Format("2006-01-02 15:04"),
+			entry.Path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		}
+	}
+}
+
+func removalBranch(entry internal.RemovalEntry) string {
+	if entry.Branch == "" {
+		return internal.DetachedBranch
+	}
+	return entry.Branch
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/no-yan/wt/internal"
+)
+
+func TestFormatRemovals(t *testing.T) {
+	removed := time.Date(2025, 1, 1, 12, 0, 0, 0, time.Local)
+	entries := []internal.RemovalEntry{
+		{Name: "spike", Path: "/repo/worktrees/spike", Head: "3f2a9c1d5e", Removed: removed},
+		{Name: "feature-auth", Path: "/repo/worktrees/feature-auth", Branch: "feature/auth", Head: "8d41e07aa", Removed: removed},
+	}
+
+	var buf bytes.Buffer
+	formatRemovals(entries, &buf)
+
+	want := "spike         detached HEAD  3f2a9c1  2025-01-01 12:00  /repo/worktrees/spike\n" +
+		"feature-auth  feature/auth   8d41e07  2025-01-01 12:00  /repo/worktrees/feature-auth\n"
+	if got := buf.String(); got != want {
+		t.Errorf("formatRemovals() =\n%s\nwant\n%s", got, want)
+	}
+}
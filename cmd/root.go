@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"os"
+
 	"github.com/spf13/cobra"
 )
 
@@ -8,9 +10,12 @@ var rootCmd = &cobra.Command{
 	Use:   "wt",
 	Short: "Git worktree management made simple",
 	Long: `wt organizes git worktrees in a predictable structure and provides
-seamless navigation with zsh integration.
+seamless navigation with bash, fish and zsh integration.
+
+By default all worktrees are organized in the worktrees/ subdirectory for easy
+management. Set the wt.pathTemplate git config key to choose another layout, e.g.
 
-All worktrees are organized in the worktrees/ subdirectory for easy management.
+  git config wt.pathTemplate '{{.RepoParent}}/{{.RepoName}}.worktrees/{{.Name}}'
 
 Built-in aliases:
   sw  - alias for switch
@@ -21,14 +26,23 @@ Use "wt [command] --help" for more information about a command.`,
 }
 
 func Execute() error {
+	rootCmd.SetArgs(switchHistoryArgs(os.Args[1:]))
 	return rootCmd.Execute()
 }
 
 func init() {
+	// Completion comes with 'wt shell-init', so the default command is hidden
+	rootCmd.CompletionOptions.HiddenDefaultCmd = true
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(addCmd)
 	rootCmd.AddCommand(switchCmd)
+	rootCmd.AddCommand(recentCmd)
 	rootCmd.AddCommand(removeCmd)
+	rootCmd.AddCommand(restoreCmd)
 	rootCmd.AddCommand(cleanCmd)
+	rootCmd.AddCommand(repairCmd)
+	rootCmd.AddCommand(duCmd)
+	rootCmd.AddCommand(cloneCmd)
+	rootCmd.AddCommand(promptCmd)
 	rootCmd.AddCommand(shellInitCmd)
 }
@@ -2,108 +2,262 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
+// shellIntegrations generates the integration code for each supported shell.
+var shellIntegrations = map[string]func() string{
+	"bash": generateBashIntegration,
+	"fish": generateFishIntegration,
+	"zsh":  generateZshIntegration,
+}
+
 var shellInitCmd = &cobra.Command{
-	Use:   "shell-init",
+	Use:   "shell-init [bash|fish|zsh]",
 	Short: "Generate shell integration code",
-	Long: `Generate shell integration code for zsh.
+	Long: `Generate shell integration code for bash, fish or zsh. Without an argument,
+the shell is taken from $SHELL.
 
 Add this to your ~/.zshrc:
-  eval "$(wt shell-init)"
+  eval "$(wt shell-init zsh)"
+
+or to your ~/.bashrc:
+  eval "$(wt shell-init bash)"
+
+or to your ~/.config/fish/config.fish:
+  wt shell-init fish | source
 
 This enables the 'wt switch' command to actually change directories.`,
+	ValidArgs: []string{"bash", "fish", "zsh"},
+	Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Print(generateZshIntegration())
+		shell := ""
+		if len(args) > 0 {
+			shell = args[0]
+		} else {
+			var err error
+			if shell, err = detectShell(os.Getenv("SHELL")); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		fmt.Print(shellIntegrations[shell]())
 	},
 }
 
-func generateZshIntegration() string {
-	return `# wt shell integration for zsh
-function wt() {
+// detectShell returns the supported shell named by the path in $SHELL.
+func detectShell(shellPath string) (string, error) {
+	if shellPath == "" {
+		return "", fmt.Errorf("$SHELL is not set; use 'wt shell-init bash', 'wt shell-init fish' or 'wt shell-init zsh'")
+	}
+	shell := filepath.Base(shellPath)
+	if _, ok := shellIntegrations[shell]; !ok {
+		return "", fmt.Errorf("shell %q is not supported; use 'wt shell-init bash', 'wt shell-init fish' or 'wt shell-init zsh'", shell)
+	}
+	return shell, nil
+}
+
+// posixWrapper is the wt function shared by bash and zsh. The binary
+// resolves switch targets, including - and -N from the shared history, runs
+// the picker and keeps the subdirectory; the function only changes directory.
+const posixWrapper = `function wt() {
   case "$1" in
     switch|sw)
-      if [ $# -eq 2 ]; then
-        if [ "$2" = "-" ]; then
-          # Handle switch to previous worktree
-          if [ -n "$WRKT_OLDPWD" ]; then
-            local current_pwd="$PWD"
-            cd "$WRKT_OLDPWD"
-            export WRKT_OLDPWD="$current_pwd"
-          else
-            echo "wt: no previous worktree" >&2
-            return 1
-          fi
-        else
-          # Handle switch to named worktree
-          local target_path
-          target_path=$(command wt switch "$2" 2>/dev/null)
-          if [ $? -eq 0 ] && [ -n "$target_path" ]; then
-            # Save current location as previous
-            export WRKT_OLDPWD="$PWD"
-            cd "$target_path"
-          else
-            command wt switch "$2"
-          fi
+      local arg
+      for arg in "$@"; do
+        case "$arg" in
+          -h|--help)
+            command wt "$@"
+            return
+            ;;
+        esac
+      done
+      # Messages go to stderr; only the path is captured
+      local target_path
+      target_path=$(command wt "$@") || return 1
+      if [ -n "$target_path" ]; then
+        cd "$target_path"
+      fi
+      ;;
+    remove|rm)
+      # Leave a worktree that is about to be removed, so the shell does not
+      # end up in a deleted directory
+      local here targets inside="" target landing rc
+      for target in "$@"; do
+        if [ "$target" = "--dry-run" ]; then
+          command wt "$@"
+          return
         fi
-      else
-        echo "Usage: wt switch <name>" >&2
+      done
+      here=$(pwd -P)
+      targets=$(command wt "$@" --dry-run 2>/dev/null)
+      while IFS= read -r target; do
+        case "$here/" in
+          "$target"/*) inside=1 ;;
+        esac
+      done <<< "$targets"
+
+      if [ -z "$inside" ]; then
+        command wt "$@"
+        return
+      fi
+
+      landing=$(git worktree list --porcelain 2>/dev/null | WT_REMOVING=$targets awk '` + landingScript + `')
+      if [ -z "$landing" ] || ! cd "$landing"; then
+        echo "wt: no worktree to move to, not removing the current one" >&2
         return 1
       fi
+
+      # Run from the original directory so '.' still refers to it
+      (cd "$here" && command wt "$@")
+      rc=$?
+      # Go back unless the directory is gone: the removal may have failed
+      # or been declined at the prompt
+      if [ -d "$here" ]; then
+        cd "$here"
+      fi
+      return $rc
       ;;
     *)
       command wt "$@"
       ;;
   esac
 }
+`
 
-# Tab completion for wt
-function _wt_completion() {
-  local state
-  _arguments \
-    '1: :->commands' \
-    '*: :->args'
-  
-  case $state in
-    commands)
-      _values 'commands' \
-        'add[Add a new worktree]' \
-        'list[List all worktrees]' \
-        'ls[List all worktrees (alias)]' \
-        'switch[Switch to a worktree]' \
-        'sw[Switch to a worktree (alias)]' \
-        'remove[Remove a worktree]' \
-        'rm[Remove a worktree (alias)]' \
-        'shell-init[Generate shell integration]' \
-        'help[Help about any command]'
-      ;;
-    args)
-      case $words[2] in
-        switch|sw)
-          local worktrees
-          worktrees=($(command wt list --names-only 2>/dev/null))
-          # Add the previous worktree option
-          worktrees+=("-")
-          _values 'worktrees' $worktrees
-          ;;
-        remove|rm)
-          local worktrees
-          worktrees=($(command wt list --names-only 2>/dev/null | grep -v -E '^(main|master)$'))
-          _values 'worktrees' $worktrees
-          ;;
-        add)
-          # Complete with branch names
-          local branches
-          branches=($(git branch -r 2>/dev/null | sed 's|.*origin/||' | grep -v HEAD))
-          _values 'branches' $branches
-          ;;
-      esac
-      ;;
-  esac
+func generateZshIntegration() string {
+	completion := cobraCompletion(rootCmd.GenZshCompletion)
+	// compdef is only defined once compinit has run
+	completion = strings.Replace(completion, "\ncompdef _wt wt\n",
+		"\nif (( $+functions[compdef] )); then\n  compdef _wt wt\nfi\n", 1)
+	return "# wt shell integration for zsh\n" + posixWrapper + posixPrompt + "\n" + completion
 }
 
-compdef _wt_completion wt
+func generateBashIntegration() string {
+	return "# wt shell integration for bash\n" + posixWrapper + posixPrompt + bashCompletionFallback + "\n" +
+		cobraCompletion(func(w io.Writer) error { return rootCmd.GenBashCompletionV2(w, true) })
+}
+
+// posixPrompt defines wt_prompt, printing the worktree segment for PS1 in
+// bash or PROMPT in zsh.
+const posixPrompt = `
+# Prompt segment, e.g. PS1='$(wt_prompt) \$ ' in bash, or
+# setopt prompt_subst; PROMPT='$(wt_prompt) %# ' in zsh
+wt_prompt() {
+  command wt prompt --cache 2s "$@" 2>/dev/null
+}
+`
+
+// fishPrompt is the fish equivalent of posixPrompt.
+const fishPrompt = `
+# Prompt segment, e.g. in fish_prompt: echo -n (wt_prompt)' > '
+function wt_prompt
+  command wt prompt --cache 2s $argv 2>/dev/null
+end
 `
+
+// bashCompletionFallback stands in for the one function of the
+// bash-completion package that cobra's script needs, so completion works
+// where the package is not installed.
+const bashCompletionFallback = `
+if ! declare -F _get_comp_words_by_ref >/dev/null 2>&1; then
+  _get_comp_words_by_ref() {
+    cur=${COMP_WORDS[COMP_CWORD]}
+    prev=${COMP_WORDS[COMP_CWORD-1]}
+    words=("${COMP_WORDS[@]}")
+    cword=$COMP_CWORD
+  }
+fi
+`
+
+func generateFishIntegration() string {
+	return fishWrapper + fishPrompt + "\n" +
+		cobraCompletion(func(w io.Writer) error { return rootCmd.GenFishCompletion(w, true) })
+}
+
+// cobraCompletion returns the completion script cobra generates for the
+// commands' ValidArgsFunction and flag completions. The script completes
+// through 'wt __complete', which the wt function passes to the binary.
+func cobraCompletion(generate func(io.Writer) error) string {
+	var script strings.Builder
+	if err := generate(&script); err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating completion: %v\n", err)
+		os.Exit(1)
+	}
+	return script.String()
 }
+
+// landingScript is the awk program the remove wrappers use to pick the
+// directory to move to, reading 'git worktree list --porcelain': the first
+// worktree that is not being removed ($WT_REMOVING, one path per line), or
+// else the repository root. A bare repository is never a place to land; in
+// the layout of 'wt clone --bare' its root is the directory holding .bare.
+const landingScript = `
+BEGIN { n = split(ENVIRON["WT_REMOVING"], t, "\n"); for (i = 1; i <= n; i++) removing[t[i]] = 1 }
+/^worktree / { path = substr($0, 10); next }
+$0 == "bare" { root = path; sub(/\/\.bare$/, "", root); path = ""; next }
+/^$/ { if (path != "" && !(path in removing)) { print path; found = 1; exit } path = "" }
+END { if (found) exit; if (path != "" && !(path in removing)) print path; else if (root != "") print root }
+`
+
+// fishWrapper is the fish equivalent of posixWrapper.
+const fishWrapper = `# wt shell integration for fish
+function wt --description 'Git worktree management made simple'
+  switch "$argv[1]"
+    case switch sw
+      if contains -- -h $argv; or contains -- --help $argv
+        command wt $argv
+        return
+      end
+      # Messages go to stderr; only the path is captured
+      set -l target_path (command wt $argv); or return 1
+      if test -n "$target_path"
+        cd $target_path
+      end
+    case remove rm
+      # Leave a worktree that is about to be removed, so the shell does not
+      # end up in a deleted directory
+      if contains -- --dry-run $argv
+        command wt $argv
+        return
+      end
+      set -l here (pwd -P)
+      set -l targets (command wt $argv --dry-run 2>/dev/null)
+      set -l inside
+      for target in $targets
+        if string match -q -- "$target/*" "$here/"
+          set inside 1
+        end
+      end
+
+      if test -z "$inside"
+        command wt $argv
+        return
+      end
+
+      set -l removing (string join \n -- $targets | string collect)
+      set -l landing (git worktree list --porcelain 2>/dev/null | env WT_REMOVING=$removing awk '` + landingScript + `')
+      if test -z "$landing"; or not cd $landing
+        echo "wt: no worktree to move to, not removing the current one" >&2
+        return 1
+      end
+
+      # Run from the original directory so '.' still refers to it
+      sh -c 'cd "$1" && shift && exec wt "$@"' sh $here $argv
+      set -l rc $status
+      # Go back unless the directory is gone: the removal may have failed
+      # or been declined at the prompt
+      if test -d $here
+        cd $here
+      end
+      return $rc
+    case '*'
+      command wt $argv
+  end
+end
+`
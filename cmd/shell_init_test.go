@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectShell(t *testing.T) {
+	tests := []struct {
+		shellPath string
+		want      string
+		wantErr   bool
+	}{
+		{shellPath: "/bin/zsh", want: "zsh"},
+		{shellPath: "/usr/local/bin/bash", want: "bash"},
+		{shellPath: "/opt/homebrew/bin/fish", want: "fish"},
+		{shellPath: "/bin/tcsh", wantErr: true},
+		{shellPath: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := detectShell(tt.shellPath)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("detectShell(%q) error = %v, wantErr %v", tt.shellPath, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("detectShell(%q) = %q, want %q", tt.shellPath, got, tt.want)
+		}
+	}
+}
+
+// fakeWt stands in for the wt binary: switch prints a path on stdout and a
+// message on stderr, as the real one does, remove deletes the worktree
+// unless it is second, as if declined at the prompt, prompt prints a
+// segment and __complete answers like cobra.
+const fakeWt = `#!/bin/sh
+case "$1" in
+  switch|sw)
+    case "$2" in
+      -) echo "$WT_TEST_ROOT/worktrees/second" ;;
+      missing) echo 'Error: worktree "missing" not found' >&2; exit 1 ;;
+      *) echo "Switching to $2" >&2; echo "$WT_TEST_ROOT/worktrees/$2" ;;
+    esac
+    ;;
+  remove|rm)
+    case "$3" in
+      --dry-run) echo "$WT_TEST_ROOT/worktrees/$2" ;;
+      *) [ "$2" = second ] && echo "Removal cancelled" && exit 0
+         rm -rf "$WT_TEST_ROOT/worktrees/$2" && echo "Removed worktree: $2" ;;
+    esac
+    ;;
+  prompt) printf 'second *' ;;
+  __complete) printf 'feat\tfeature, clean\n:4\n' ;;
+esac
+`
+
+// TestShellIntegration sources the generated code in each shell that is
+// installed and drives the wt function with a fake binary.
+func TestShellIntegration(t *testing.T) {
+	tests := []struct {
+		shell string
+		args  []string
+		// script runs after the integration is loaded from $WT_TEST_INIT
+		script string
+		// bare removes the current worktree in a clone made by
+		// 'wt clone --bare', rooted at $WT_TEST_ROOT
+		bare string
+		// completion prints the candidates for 'wt switch f', if testable
+		completion string
+	}{
+		{
+			shell: "bash",
+			args:  []string{"--norc", "--noprofile", "-c"},
+			script: `. "$WT_TEST_INIT"
+cd "$WT_TEST_ROOT/worktrees/second"
+wt switch feat; pwd -P
+wt switch -; pwd -P
+wt switch missing; echo "rc=$?"; pwd -P
+wt_prompt; echo
+wt rm second >/dev/null; pwd -P
+cd "$WT_TEST_ROOT/worktrees/feat"
+wt rm feat >/dev/null; pwd -P`,
+			bare: `. "$WT_TEST_INIT"
+cd "$WT_TEST_ROOT/worktrees/main"
+wt rm main >/dev/null; pwd -P
+git worktree prune
+cd "$WT_TEST_ROOT/worktrees/feat"
+wt rm feat >/dev/null; pwd -P`,
+			completion: `. "$WT_TEST_INIT"
+COMP_WORDS=(wt switch f); COMP_CWORD=2; COMP_LINE="wt switch f"; COMP_POINT=${#COMP_LINE}
+__start_wt wt f switch; echo "${COMPREPLY[@]}"`,
+		},
+		{
+			shell: "zsh",
+			args:  []string{"-f", "-c"},
+			script: `. "$WT_TEST_INIT"
+cd "$WT_TEST_ROOT/worktrees/second"
+wt switch feat; pwd -P
+wt switch -; pwd -P
+wt switch missing; echo "rc=$?"; pwd -P
+wt_prompt; echo
+wt rm second >/dev/null; pwd -P
+cd "$WT_TEST_ROOT/worktrees/feat"
+wt rm feat >/dev/null; pwd -P`,
+			bare: `. "$WT_TEST_INIT"
+cd "$WT_TEST_ROOT/worktrees/main"
+wt rm main >/dev/null; pwd -P
+git worktree prune
+cd "$WT_TEST_ROOT/worktrees/feat"
+wt rm feat >/dev/null; pwd -P`,
+		},
+		{
+			shell: "fish",
+			args:  []string{"--no-config", "-c"},
+			script: `source $WT_TEST_INIT
+cd $WT_TEST_ROOT/worktrees/second
+wt switch feat; pwd -P
+wt switch -; pwd -P
+wt switch missing; echo "rc=$status"; pwd -P
+wt_prompt; echo
+wt rm second >/dev/null; pwd -P
+cd $WT_TEST_ROOT/worktrees/feat
+wt rm feat >/dev/null; pwd -P`,
+			bare: `source $WT_TEST_INIT
+cd $WT_TEST_ROOT/worktrees/main
+wt rm main >/dev/null; pwd -P
+git worktree prune
+cd $WT_TEST_ROOT/worktrees/feat
+wt rm feat >/dev/null; pwd -P`,
+			completion: `source $WT_TEST_INIT
+complete -C 'wt switch f' | string split -f1 \t`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			shellPath, err := exec.LookPath(tt.shell)
+			if err != nil {
+				t.Skipf("%s is not installed", tt.shell)
+			}
+
+			root, err := filepath.EvalSymlinks(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			bin := filepath.Join(root, "bin")
+			for _, dir := range []string{bin, filepath.Join(root, "worktrees", "feat"), filepath.Join(root, "worktrees", "second")} {
+				if err := os.MkdirAll(dir, 0o755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := os.WriteFile(filepath.Join(bin, "wt"), []byte(fakeWt), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			// The remove wrapper lands in the main worktree git lists first
+			if err := runGitCommand(root, "git", "init", "-q"); err != nil {
+				t.Skipf("git init failed: %v", err)
+			}
+			initPath := filepath.Join(root, "init."+tt.shell)
+			if err := os.WriteFile(initPath, []byte(shellIntegrations[tt.shell]()), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			run := func(script string, testRoot string) (string, string) {
+				cmd := exec.Command(shellPath, append(tt.args, script)...)
+				cmd.Env = append(os.Environ(),
+					"PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"),
+					"WT_TEST_ROOT="+testRoot,
+					"WT_TEST_INIT="+initPath,
+				)
+				var stdout, stderr strings.Builder
+				cmd.Stdout, cmd.Stderr = &stdout, &stderr
+				if err := cmd.Run(); err != nil {
+					t.Fatalf("%s failed: %v\nstdout:\n%s\nstderr:\n%s", tt.shell, err, stdout.String(), stderr.String())
+				}
+				return stdout.String(), stderr.String()
+			}
+
+			stdout, stderr := run(tt.script, root)
+			want := strings.Join([]string{
+				root + "/worktrees/feat",
+				root + "/worktrees/second",
+				"rc=1",
+				root + "/worktrees/second",
+				"second *",
+				root + "/worktrees/second",
+				root,
+			}, "\n") + "\n"
+			if stdout != want {
+				t.Errorf("stdout:\n%s\nwant:\n%s", stdout, want)
+			}
+			for _, msg := range []string{"Switching to feat", `worktree "missing" not found`} {
+				if !strings.Contains(stderr, msg) {
+					t.Errorf("stderr %q does not contain %q", stderr, msg)
+				}
+			}
+			if _, err := os.Stat(filepath.Join(root, "worktrees", "feat")); !os.IsNotExist(err) {
+				t.Error("feat was not removed")
+			}
+
+			// The bare repository is listed first; the shell lands in the
+			// next worktree, or the clone's root when there is none left
+			bareRoot := filepath.Join(root, "clone")
+			for _, args := range [][]string{
+				{"git", "init", "-q", "src"},
+				{"git", "-C", "src", "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "Initial commit"},
+				{"git", "clone", "-q", "--bare", "src", "clone/.bare"},
+				{"git", "-C", "clone/.bare", "worktree", "add", "-q", "--detach", bareRoot + "/worktrees/main"},
+				{"git", "-C", "clone/.bare", "worktree", "add", "-q", "--detach", bareRoot + "/worktrees/feat"},
+			} {
+				if err := runGitCommand(root, args...); err != nil {
+					t.Fatalf("%v failed: %v", args, err)
+				}
+			}
+			if err := os.WriteFile(filepath.Join(bareRoot, ".git"), []byte("gitdir: ./.bare\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			stdout, _ = run(tt.bare, bareRoot)
+			want = bareRoot + "/worktrees/feat\n" + bareRoot + "\n"
+			if stdout != want {
+				t.Errorf("bare clone stdout:\n%s\nwant:\n%s", stdout, want)
+			}
+
+			if tt.completion != "" {
+				if got, _ := run(tt.completion, root); strings.TrimSpace(got) != "feat" {
+					t.Errorf("completion of 'wt switch f' = %q, want feat", got)
+				}
+			}
+		})
+	}
+}
@@ -1,68 +1,224 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/no-yan/wt/internal"
 	"github.com/spf13/cobra"
 )
 
+var (
+	switchRoot       bool
+	switchKeepSubdir bool
+	switchCreate     bool
+	switchFrom       string
+)
+
 var switchCmd = &cobra.Command{
-	Use:     "switch <name>",
+	Use:     "switch [<name>|-N|-c <branch>]",
 	Aliases: []string{"sw"},
 	Short:   "Switch to a worktree",
 	Long: `Switch to a worktree by name. This command outputs the target path for shell integration.
 
-For zsh integration, use the shell functions generated by 'wt shell-init'.`,
-	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		target := args[0]
+The target can be a worktree name, a branch name, a path in a worktree or a
+unique prefix of a name or branch. @main is the main worktree, @ the current
+one and @- the previous one.
+
+Switches are recorded in a history shared by every shell. 'wt switch -' goes
+back to the previous worktree and 'wt switch -N' to the Nth most recently
+used one; 'wt recent' lists them.
+
+The name does not have to be exact: it is matched fuzzily against worktree
+names and branches, so 'wt switch auth' finds feature-auth. A match is used
+when it is clearly the best one; otherwise the closest worktrees are suggested.
+
+Without a name on a terminal, an interactive picker lists the worktrees, most
+recently used first: type to filter, use the arrow keys or Ctrl-N/Ctrl-P to
+move, Enter to switch and Esc to cancel.
 
+From a subdirectory of the current worktree, such as services/api, the path
+printed is the same subdirectory in the target worktree, or its deepest
+ancestor that exists there. Use --root to switch to the worktree root instead,
+or set wt.keepSubdir to false to make that the default and use --keep-subdir to
+keep the subdirectory.
+
+With -c, the argument is a branch: the worktree that has it checked out is
+used, or a worktree is added for it like 'wt add' does, creating the branch
+if it does not exist. Messages go to stderr, so only the path reaches the
+shell function.
+
+For shell integration, use the function generated by 'wt shell-init'.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if switchCreate {
+			return cobra.ExactArgs(1)(cmd, args)
+		}
+		if cmd.Flags().Changed("from") {
+			return fmt.Errorf("--from requires --create")
+		}
+		return cobra.MaximumNArgs(1)(cmd, args)
+	},
+	ValidArgsFunction: completeSwitchTarget,
+	Run: func(cmd *cobra.Command, args []string) {
 		runner := internal.NewExecCommandRunner()
 		service := internal.NewGitService(runner)
+		manager := internal.NewWorktreeManager(service, runner)
+
+		repoPath, err := getRepoRoot(runner)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error finding git repository: %v\n", err)
+			os.Exit(1)
+		}
 
 		worktrees, err := service.ListWorktrees()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error listing worktrees: %v\n", err)
 			os.Exit(1)
 		}
+		resolver := newResolver(manager, repoPath, worktrees, true)
 
-		path, err := findWorktreeByName(worktrees, target)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-
-			// Show available worktrees as suggestions
-			if len(worktrees) > 0 {
-				fmt.Fprintf(os.Stderr, "\nAvailable worktrees:\n")
-				for _, wt := range worktrees {
-					fmt.Fprintf(os.Stderr, "  %s\n", wt.Name())
-				}
+		var current string
+		if wt, ok := internal.FindWorktreeByPath(worktrees, resolver.Dir); ok {
+			current = wt.Path
+		}
+
+		var target *internal.Worktree
+		if switchCreate {
+			target, err = switchCreateTarget(manager, service, repoPath, worktrees, args[0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
 			}
-			os.Exit(1)
+		} else if len(args) == 0 {
+			if !canPick() {
+				fmt.Fprintf(os.Stderr, "Error: a worktree name is required when not on a terminal\n")
+				os.Exit(1)
+			}
+			// Most recently used first, the current worktree last
+			var ordered []internal.Worktree
+			for _, recent := range internal.RecentWorktrees(worktrees, resolver.History, current, true) {
+				ordered = append(ordered, recent.Worktree)
+			}
+			if wt, ok := internal.FindWorktreeByPath(worktrees, current); ok {
+				ordered = append(ordered, *wt)
+			}
+			picked, err := pickWorktree(ordered)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			target = &picked
+		} else {
+			target, err = resolver.Resolve(args[0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				printResolveHelp(err, worktrees)
+				os.Exit(1)
+			}
+		}
+
+		if err := manager.RecordSwitch(repoPath, current, target.Path); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record switch history: %v\n", err)
 		}
 
 		// Output the path for shell integration
+		path := target.Path
+		keepSubdir := manager.KeepSubdir(repoPath)
+		if cmd.Flags().Changed("keep-subdir") || switchRoot {
+			keepSubdir = switchKeepSubdir
+		}
+		if keepSubdir && current != "" {
+			path = internal.EquivalentPath(current, resolver.Dir, target.Path)
+		}
 		fmt.Println(path)
 	},
 }
 
-func findWorktreeByName(worktrees []internal.Worktree, target string) (string, error) {
-	if len(worktrees) == 0 {
-		return "", fmt.Errorf("no worktrees found")
+func init() {
+	switchCmd.Flags().BoolVar(&switchRoot, "root", false, "Switch to the root of the worktree")
+	switchCmd.Flags().BoolVar(&switchKeepSubdir, "keep-subdir", false, "Switch to the same subdirectory in the worktree")
+	switchCmd.MarkFlagsMutuallyExclusive("root", "keep-subdir")
+	switchCmd.Flags().BoolVarP(&switchCreate, "create", "c", false, "Create a worktree for the branch if there is none")
+	switchCmd.Flags().StringVar(&switchFrom, "from", "", "With --create, create the branch at this commit, branch or tag")
+	_ = switchCmd.RegisterFlagCompletionFunc("from", completeRefs)
+}
+
+// switchCreateTarget returns the worktree of branch, adding one if there is
+// none. It reports only on stderr, as stdout is read by the shell function.
+func switchCreateTarget(manager *internal.WorktreeManager, service *internal.GitService, repoPath string, worktrees []internal.Worktree, branch string) (*internal.Worktree, error) {
+	branch = strings.TrimPrefix(branch, "refs/heads/")
+	for i := range worktrees {
+		if !worktrees[i].Detached() && worktrees[i].Branch == branch {
+			if switchFrom != "" {
+				fmt.Fprintf(os.Stderr, "Branch %s already has a worktree; ignoring --from %s\n", branch, switchFrom)
+			}
+			return &worktrees[i], nil
+		}
 	}
 
-	target = strings.TrimSpace(target)
-	if target == "" {
-		return "", fmt.Errorf("target name cannot be empty")
+	path, err := manager.AddWorktreeFrom(repoPath, branch, startPoint(service, repoPath, branch, switchFrom))
+	if err != nil {
+		return nil, fmt.Errorf("failed to add worktree: %w", err)
 	}
+	fmt.Fprintf(os.Stderr, "Added worktree: %s\n", path)
 
-	for _, wt := range worktrees {
-		if wt.Name() == target {
-			return wt.Path, nil
+	updated, err := service.ListWorktrees()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	if wt, ok := internal.FindWorktreeByPath(updated, path); ok {
+		return wt, nil
+	}
+	return &internal.Worktree{Path: path, Branch: branch}, nil
+}
+
+// printResolveHelp suggests what may have been meant by a target that
+// failed to resolve with err.
+func printResolveHelp(err error, worktrees []internal.Worktree) {
+	var resolveErr *internal.ResolveError
+	errors.As(err, &resolveErr)
+	switch {
+	case resolveErr != nil && (resolveErr.Ambiguous || resolveErr.Reason != ""):
+		// The error says it all
+	case resolveErr != nil && len(resolveErr.Candidates) > 0:
+		fmt.Fprintf(os.Stderr, "\nDid you mean:\n")
+		formatSuggestions(resolveErr.Candidates)
+	case len(worktrees) > 0:
+		// Show available worktrees as suggestions
+		fmt.Fprintf(os.Stderr, "\nAvailable worktrees:\n")
+		for _, wt := range worktrees {
+			fmt.Fprintf(os.Stderr, "  %s\n", wt.Name())
 		}
 	}
+}
 
-	return "", fmt.Errorf("worktree %q not found", target)
+// switchHistoryArgs rewrites 'wt switch -' and 'wt switch -N' to the @-
+// and @-N targets, before -N could be taken for a flag.
+func switchHistoryArgs(args []string) []string {
+	if len(args) < 2 || (args[0] != "switch" && args[0] != "sw") {
+		return args
+	}
+	rewritten := slices.Clone(args)
+	for i, arg := range rewritten[1:] {
+		if arg == "--" {
+			break
+		}
+		if arg == "-" {
+			rewritten[i+1] = internal.AliasPrevious
+		} else if n, err := strconv.Atoi(strings.TrimPrefix(arg, "-")); err == nil && strings.HasPrefix(arg, "-") && n > 0 {
+			rewritten[i+1] = internal.AliasPrevious + strconv.Itoa(n)
+		}
+	}
+	return rewritten
+}
+
+func formatSuggestions(worktrees []internal.Worktree) {
+	ws := calculateColumnWidths(worktrees)
+	for _, wt := range worktrees {
+		fmt.Fprintf(os.Stderr, "  %-*s  %s\n", ws.name, wt.Name(), wt.Branch)
+	}
 }
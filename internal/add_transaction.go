@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// addTransaction records the side effects of AddWorktree so they can be
+// undone when a later step fails.
+type addTransaction struct {
+	runner   CommandRunner
+	repoPath string
+
+	// createdDirs lists directories that did not exist before the
+	// operation, deepest first.
+	createdDirs []string
+
+	// createdBranch is set once AddWorktree has created a new branch.
+	createdBranch string
+
+	gitignorePath    string
+	gitignoreContent []byte
+	gitignoreExisted bool
+}
+
+func newAddTransaction(runner CommandRunner, repoPath string) *addTransaction {
+	return &addTransaction{runner: runner, repoPath: repoPath}
+}
+
+// recordDirs remembers every missing directory between path and its
+// nearest existing ancestor.
+func (tx *addTransaction) recordDirs(path string) {
+	for dir := filepath.Clean(path); ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(dir); err == nil {
+			return
+		}
+		tx.createdDirs = append(tx.createdDirs, dir)
+		if parent := filepath.Dir(dir); parent == dir {
+			return
+		}
+	}
+}
+
+// recordGitignore snapshots the .gitignore file so edits can be reverted.
+func (tx *addTransaction) recordGitignore(path string) error {
+	tx.gitignorePath = path
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	tx.gitignoreContent = content
+	tx.gitignoreExisted = true
+	return nil
+}
+
+// fail rolls back everything recorded so far and returns err, annotated
+// with any problems encountered while rolling back.
+func (tx *addTransaction) fail(err error) error {
+	if rbErr := tx.rollback(); rbErr != nil {
+		return fmt.Errorf("%w (rollback incomplete: %v)", err, rbErr)
+	}
+	return err
+}
+
+func (tx *addTransaction) rollback() error {
+	var problems []string
+
+	if tx.createdBranch != "" {
+		deleteCmd := fmt.Sprintf("git -C %s branch -D %s",
+			shellescape(tx.repoPath),
+			shellescape(tx.createdBranch))
+		if _, err := tx.runner.Run(deleteCmd); err != nil {
+			problems = append(problems, fmt.Sprintf("delete branch %s: %v", tx.createdBranch, err))
+		}
+	}
+
+	if tx.gitignorePath != "" {
+		if err := tx.restoreGitignore(); err != nil {
+			problems = append(problems, fmt.Sprintf("restore .gitignore: %v", err))
+		}
+	}
+
+	// Directories are removed deepest first and only while empty, so
+	// anything git or the user put there is left alone.
+	for _, dir := range tx.createdDirs {
+		if err := os.Remove(dir); err != nil && !os.IsNotExist(err) {
+			problems = append(problems, fmt.Sprintf("remove %s: %v", dir, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func (tx *addTransaction) restoreGitignore() error {
+	if !tx.gitignoreExisted {
+		if err := os.Remove(tx.gitignorePath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	current, err := os.ReadFile(tx.gitignorePath)
+	if err == nil && string(current) == string(tx.gitignoreContent) {
+		return nil
+	}
+	return os.WriteFile(tx.gitignorePath, tx.gitignoreContent, 0o644)
+}
@@ -0,0 +1,147 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ArchiveDirConfigKey is the git config key overriding where archives of
+// unpushed commits are written. Relative paths are resolved against the
+// repository. The default is wt/archive inside the git common directory.
+const ArchiveDirConfigKey = "wt.archiveDir"
+
+// unpushedCommits counts the commits of a worktree's HEAD that are missing
+// from Reference.
+type unpushedCommits struct {
+	Reference string
+	Count     int
+}
+
+// ArchiveMetadata is written next to each archive bundle.
+type ArchiveMetadata struct {
+	Name      string    `json:"name"`
+	Path      string    `json:"path"`
+	Branch    string    `json:"branch,omitempty"`
+	Head      string    `json:"head"`
+	Reference string    `json:"reference"`
+	Commits   int       `json:"commits"`
+	Bundle    string    `json:"bundle"`
+	Created   time.Time `json:"created"`
+}
+
+// countUnpushed compares the HEAD of wt with its upstream, or with base when
+// there is none. Without either there is nothing to compare with and no
+// commits are reported.
+func (wm *WorktreeManager) countUnpushed(wt *Worktree, base string) (unpushedCommits, error) {
+	reference := ""
+	upstreamCmd := fmt.Sprintf("git -C %s rev-parse --abbrev-ref --symbolic-full-name %s",
+		shellescape(wt.Path),
+		shellescape("@{upstream}"))
+	// Fails for detached worktrees and branches without upstream
+	if output, err := wm.runner.Run(upstreamCmd); err == nil {
+		reference = strings.TrimSpace(output)
+	}
+	if reference == "" && wt.Branch != base {
+		reference = base
+	}
+	if reference == "" {
+		return unpushedCommits{}, nil
+	}
+
+	countCmd := fmt.Sprintf("git -C %s rev-list --count %s",
+		shellescape(wt.Path),
+		shellescape(reference+"..HEAD"))
+	output, err := wm.runner.Run(countCmd)
+	if err != nil {
+		return unpushedCommits{}, fmt.Errorf("failed to count commits not in %s: %w", reference, err)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(output))
+	if err != nil {
+		return unpushedCommits{}, fmt.Errorf("failed to count commits not in %s: unexpected output %q", reference, strings.TrimSpace(output))
+	}
+	return unpushedCommits{Reference: reference, Count: count}, nil
+}
+
+// archiveDir returns the directory archives of repoPath are written to.
+func (wm *WorktreeManager) archiveDir(repoPath string) (string, error) {
+	configCmd := fmt.Sprintf("git -C %s config --get %s", shellescape(repoPath), ArchiveDirConfigKey)
+	if output, err := wm.runner.Run(configCmd); err == nil {
+		if dir := strings.TrimSpace(output); dir != "" {
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(repoPath, dir)
+			}
+			return dir, nil
+		}
+	}
+
+	commonDir, err := wm.gitCommonDir(repoPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(commonDir, "wt", "archive"), nil
+}
+
+// gitCommonDir returns the git directory shared by all worktrees of repoPath.
+func (wm *WorktreeManager) gitCommonDir(repoPath string) (string, error) {
+	output, err := wm.runner.Run(fmt.Sprintf("git -C %s rev-parse --git-common-dir", shellescape(repoPath)))
+	if err != nil {
+		return "", fmt.Errorf("failed to locate git directory: %w", err)
+	}
+	dir := strings.TrimSpace(output)
+	if dir == "" {
+		return "", fmt.Errorf("failed to locate git directory of %s", repoPath)
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(repoPath, dir)
+	}
+	return filepath.Clean(dir), nil
+}
+
+// archiveUnpushed writes a git bundle with the unpushed commits of result's
+// worktree and a JSON metadata file next to it. It returns the bundle path.
+func (wm *WorktreeManager) archiveUnpushed(repoPath string, result RemoveResult, unpushed unpushedCommits) (string, error) {
+	dir, err := wm.archiveDir(repoPath)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	created := time.Now()
+	stem := filepath.Join(dir, result.Name+"-"+created.Format("20060102-150405"))
+	bundle := stem + ".bundle"
+
+	bundleCmd := fmt.Sprintf("git -C %s bundle create %s HEAD %s",
+		shellescape(result.Path),
+		shellescape(bundle),
+		shellescape("^"+unpushed.Reference))
+	if _, err := wm.runner.Run(bundleCmd); err != nil {
+		return "", fmt.Errorf("git bundle failed: %w", err)
+	}
+
+	metadata := ArchiveMetadata{
+		Name:      result.Name,
+		Path:      result.Path,
+		Branch:    result.Branch,
+		Head:      result.head,
+		Reference: unpushed.Reference,
+		Commits:   unpushed.Count,
+		Bundle:    bundle,
+		Created:   created,
+	}
+	content, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(stem+".json", append(content, '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write archive metadata: %w", err)
+	}
+
+	return bundle, nil
+}
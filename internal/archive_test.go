@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// bundleRunner accepts any git bundle command, whose file name contains a
+// timestamp, and records it.
+type bundleRunner struct {
+	MockCommandRunner
+	bundles []string
+}
+
+func (b *bundleRunner) Run(command string) (string, error) {
+	if strings.Contains(command, " bundle create ") {
+		b.bundles = append(b.bundles, command)
+		return "", nil
+	}
+	return b.MockCommandRunner.Run(command)
+}
+
+func TestWorktreeManager_RemoveWorktrees_Unpushed(t *testing.T) {
+	const wtPath = "/repo/worktrees/feature"
+	upstreamCmd := "git -C " + wtPath + " rev-parse --abbrev-ref --symbolic-full-name '@{upstream}'"
+
+	newRunner := func() *bundleRunner {
+		return &bundleRunner{MockCommandRunner: MockCommandRunner{outputs: map[string]string{
+			"git worktree list --porcelain": "worktree /repo\nHEAD abc123\nbranch refs/heads/main\n\n" +
+				"worktree " + wtPath + "\nHEAD def456\nbranch refs/heads/feature",
+			"git -C /repo status --porcelain":                             "",
+			"git -C " + wtPath + " status --porcelain":                    "",
+			"git -C /repo symbolic-ref --short HEAD":                      "main\n",
+			upstreamCmd:                                                   "origin/feature\n",
+			"git -C " + wtPath + " rev-list --count origin/feature..HEAD": "2\n",
+			"git -C /repo worktree remove " + wtPath:                      "",
+		}}}
+	}
+
+	t.Run("unpushed commits block removal", func(t *testing.T) {
+		runner := newRunner()
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		_, err := manager.RemoveWorktrees("/repo", []string{"feature"}, RemoveOptions{})
+		if err == nil || !strings.Contains(err.Error(), "2 commit(s) not in origin/feature") {
+			t.Errorf("RemoveWorktrees() error = %v, want unpushed commits error", err)
+		}
+	})
+
+	t.Run("base branch is used without upstream", func(t *testing.T) {
+		runner := newRunner()
+		delete(runner.outputs, upstreamCmd)
+		runner.outputs["git -C "+wtPath+" rev-list --count main..HEAD"] = "1\n"
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		_, err := manager.RemoveWorktrees("/repo", []string{"feature"}, RemoveOptions{})
+		if err == nil || !strings.Contains(err.Error(), "1 commit(s) not in main") {
+			t.Errorf("RemoveWorktrees() error = %v, want unpushed commits error", err)
+		}
+	})
+
+	t.Run("pushed worktree is removed", func(t *testing.T) {
+		runner := newRunner()
+		runner.outputs["git -C "+wtPath+" rev-list --count origin/feature..HEAD"] = "0\n"
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		if _, err := manager.RemoveWorktrees("/repo", []string{"feature"}, RemoveOptions{}); err != nil {
+			t.Errorf("RemoveWorktrees() error = %v", err)
+		}
+	})
+
+	t.Run("archive writes bundle and metadata", func(t *testing.T) {
+		archiveDir := t.TempDir()
+		runner := newRunner()
+		runner.outputs["git -C /repo config --get "+ArchiveDirConfigKey] = archiveDir + "\n"
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		results, err := manager.RemoveWorktrees("/repo", []string{"feature"}, RemoveOptions{Archive: true})
+		if err != nil {
+			t.Fatalf("RemoveWorktrees() error = %v", err)
+		}
+
+		bundle := results[0].ArchivePath
+		if filepath.Dir(bundle) != archiveDir || !strings.HasSuffix(bundle, ".bundle") {
+			t.Errorf("ArchivePath = %q, want a bundle in %s", bundle, archiveDir)
+		}
+		wantCmd := "git -C " + wtPath + " bundle create " + bundle + " HEAD ^origin/feature"
+		if len(runner.bundles) != 1 || runner.bundles[0] != wantCmd {
+			t.Errorf("bundle commands = %v, want [%s]", runner.bundles, wantCmd)
+		}
+
+		content, err := os.ReadFile(strings.TrimSuffix(bundle, ".bundle") + ".json")
+		if err != nil {
+			t.Fatalf("metadata not written: %v", err)
+		}
+		var metadata ArchiveMetadata
+		if err := json.Unmarshal(content, &metadata); err != nil {
+			t.Fatal(err)
+		}
+		if metadata.Branch != "feature" || metadata.Head != "def456" || metadata.Commits != 2 || metadata.Reference != "origin/feature" {
+			t.Errorf("metadata = %+v", metadata)
+		}
+	})
+}
+
+func TestWorktreeManager_ArchiveDir(t *testing.T) {
+	tests := []struct {
+		name    string
+		outputs map[string]string
+		want    string
+	}{
+		{
+			name:    "default inside git directory",
+			outputs: map[string]string{"git -C /repo rev-parse --git-common-dir": ".git\n"},
+			want:    "/repo/.git/wt/archive",
+		},
+		{
+			name:    "bare repository",
+			outputs: map[string]string{"git -C /repo rev-parse --git-common-dir": ".\n"},
+			want:    "/repo/wt/archive",
+		},
+		{
+			name:    "configured relative directory",
+			outputs: map[string]string{"git -C /repo config --get " + ArchiveDirConfigKey: "archive\n"},
+			want:    "/repo/archive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &MockCommandRunner{outputs: tt.outputs}
+			manager := NewWorktreeManager(NewGitService(runner), runner)
+
+			got, err := manager.archiveDir("/repo")
+			if err != nil {
+				t.Fatalf("archiveDir() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("archiveDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BackupRefPrefix is the namespace of the refs holding changes saved by a
+// forced removal. Each ref points at a stash commit, so the changes can be
+// brought back with git stash apply.
+const BackupRefPrefix = "refs/wt/backups/"
+
+// backupChanges stashes the uncommitted changes of the worktree at
+// worktreePath, including untracked files, and keeps the stash commit under
+// BackupRefPrefix so it survives the worktree. It returns the ref name.
+func (wm *WorktreeManager) backupChanges(repoPath, worktreePath, name string) (string, error) {
+	// refs/stash is shared by all worktrees; remember it to be sure the
+	// push below really created a new entry.
+	before, _ := wm.runner.Run(fmt.Sprintf("git -C %s rev-parse --verify --quiet refs/stash", shellescape(worktreePath)))
+
+	pushCmd := fmt.Sprintf("git -C %s stash push --include-untracked --message %s",
+		shellescape(worktreePath),
+		shellescape("wt backup of "+name))
+	if _, err := wm.runner.Run(pushCmd); err != nil {
+		return "", fmt.Errorf("git stash failed: %w", err)
+	}
+
+	output, err := wm.runner.Run(fmt.Sprintf("git -C %s rev-parse --verify --quiet refs/stash", shellescape(worktreePath)))
+	commit := strings.TrimSpace(output)
+	if err != nil || commit == "" || commit == strings.TrimSpace(before) {
+		return "", fmt.Errorf("git stash did not record any changes")
+	}
+
+	ref := wm.freeBackupRef(repoPath, name)
+	updateCmd := fmt.Sprintf("git -C %s update-ref %s %s",
+		shellescape(repoPath),
+		shellescape(ref),
+		shellescape(commit))
+	if _, err := wm.runner.Run(updateCmd); err != nil {
+		wm.popStash(worktreePath)
+		return "", fmt.Errorf("failed to create %s: %w", ref, err)
+	}
+
+	// The ref now keeps the commit alive; drop the stash entry so it does
+	// not show up in the stash list of every other worktree.
+	dropCmd := fmt.Sprintf("git -C %s stash drop --quiet", shellescape(worktreePath))
+	if _, err := wm.runner.Run(dropCmd); err != nil {
+		return "", fmt.Errorf("failed to drop stash entry: %w", err)
+	}
+
+	// Anything left behind would be lost by the removal
+	statusCmd := fmt.Sprintf("git -C %s status --porcelain", shellescape(worktreePath))
+	status, err := wm.runner.Run(statusCmd)
+	if err != nil {
+		return ref, fmt.Errorf("failed to check worktree status: %w", err)
+	}
+	if strings.TrimSpace(status) != "" {
+		return ref, fmt.Errorf("changes remain after the backup")
+	}
+
+	return ref, nil
+}
+
+// freeBackupRef returns the backup ref for name, adding a numeric suffix
+// when an earlier backup of the same name exists.
+func (wm *WorktreeManager) freeBackupRef(repoPath, name string) string {
+	ref := BackupRefPrefix + name
+	for i := 2; ; i++ {
+		verifyCmd := fmt.Sprintf("git -C %s rev-parse --verify --quiet %s", shellescape(repoPath), shellescape(ref))
+		if _, err := wm.runner.Run(verifyCmd); err != nil {
+			return ref
+		}
+		ref = fmt.Sprintf("%s%s-%d", BackupRefPrefix, name, i)
+	}
+}
+
+func (wm *WorktreeManager) popStash(worktreePath string) {
+	_, _ = wm.runner.Run(fmt.Sprintf("git -C %s stash pop", shellescape(worktreePath)))
+}
+
+// restoreBackups applies the backups of results to their worktrees again.
+// It is used when a later step fails before anything was removed.
+func (wm *WorktreeManager) restoreBackups(results []RemoveResult) error {
+	var problems []string
+	for _, r := range results {
+		if r.BackupRef == "" {
+			continue
+		}
+		applyCmd := fmt.Sprintf("git -C %s stash apply %s", shellescape(r.Path), shellescape(r.BackupRef))
+		if _, err := wm.runner.Run(applyCmd); err != nil {
+			problems = append(problems, fmt.Sprintf("%s from %s: %v", r.Name, r.BackupRef, err))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("failed to restore changes from backup (%s)", strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Branch is a local or remote-tracking branch.
+type Branch struct {
+	// Name is the branch name without refs/heads/ or the remote.
+	Name string
+	// Remote is the remote of a remote-tracking branch, empty for a local
+	// branch.
+	Remote string
+}
+
+// Ref returns the short name git accepts for the branch, such as
+// origin/feature for a remote-tracking branch.
+func (b Branch) Ref() string {
+	if b.Remote == "" {
+		return b.Name
+	}
+	return b.Remote + "/" + b.Name
+}
+
+// ListBranches returns the local branches of repoPath followed by its
+// remote-tracking branches, each sorted by name. The symbolic HEAD of a
+// remote is left out.
+func (g *GitService) ListBranches(repoPath string) ([]Branch, error) {
+	output, err := g.runner.Run(fmt.Sprintf("git -C %s for-each-ref --format=%s refs/heads refs/remotes",
+		shellescape(repoPath),
+		shellescape("%(refname)")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	var remotes []string
+	if strings.Contains(output, "refs/remotes/") {
+		remoteOutput, err := g.runner.Run(fmt.Sprintf("git -C %s remote", shellescape(repoPath)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list remotes: %w", err)
+		}
+		remotes = strings.Fields(remoteOutput)
+	}
+	return parseBranches(output, remotes), nil
+}
+
+// parseBranches parses full ref names, one per line. A remote-tracking ref
+// is split at the longest of remotes it starts with, since remote names may
+// contain slashes.
+func parseBranches(output string, remotes []string) []Branch {
+	var local, remote []Branch
+	for _, ref := range strings.Fields(output) {
+		if name, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+			local = append(local, Branch{Name: name})
+			continue
+		}
+		rest, ok := strings.CutPrefix(ref, "refs/remotes/")
+		if !ok {
+			continue
+		}
+		var branch Branch
+		for _, r := range remotes {
+			if name, ok := strings.CutPrefix(rest, r+"/"); ok && len(r) > len(branch.Remote) {
+				branch = Branch{Name: name, Remote: r}
+			}
+		}
+		if branch.Remote != "" && branch.Name != "HEAD" {
+			remote = append(remote, branch)
+		}
+	}
+
+	byName := func(a, b Branch) int { return strings.Compare(a.Name, b.Name) }
+	slices.SortStableFunc(local, byName)
+	slices.SortStableFunc(remote, byName)
+	return append(local, remote...)
+}
+
+// TrackingStartPoint returns the remote-tracking branch a new local branch
+// called name should start at: the only one with that name, if there is no
+// local branch of that name yet.
+func TrackingStartPoint(branches []Branch, name string) (string, bool) {
+	var found []Branch
+	for _, b := range branches {
+		if b.Name != name {
+			continue
+		}
+		if b.Remote == "" {
+			return "", false
+		}
+		found = append(found, b)
+	}
+	if len(found) != 1 {
+		return "", false
+	}
+	return found[0].Ref(), true
+}
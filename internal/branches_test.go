@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseBranches(t *testing.T) {
+	output := "refs/heads/main\n" +
+		"refs/heads/feature/auth\n" +
+		"refs/remotes/origin/HEAD\n" +
+		"refs/remotes/origin/main\n" +
+		"refs/remotes/origin/fix/login\n" +
+		"refs/remotes/team/a/review\n"
+
+	want := []Branch{
+		{Name: "feature/auth"},
+		{Name: "main"},
+		{Name: "fix/login", Remote: "origin"},
+		{Name: "main", Remote: "origin"},
+		{Name: "review", Remote: "team/a"},
+	}
+	if got := parseBranches(output, []string{"origin", "team", "team/a"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseBranches() = %v, want %v", got, want)
+	}
+}
+
+func TestGitService_ListBranches(t *testing.T) {
+	mockRunner := &MockCommandRunner{outputs: map[string]string{
+		"git -C /repo for-each-ref --format='%(refname)' refs/heads refs/remotes": "refs/heads/main\nrefs/remotes/origin/fix\n",
+		"git -C /repo remote": "origin\n",
+	}}
+
+	got, err := NewGitService(mockRunner).ListBranches("/repo")
+	if err != nil {
+		t.Fatalf("ListBranches() error = %v", err)
+	}
+	want := []Branch{{Name: "main"}, {Name: "fix", Remote: "origin"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListBranches() = %v, want %v", got, want)
+	}
+}
+
+func TestTrackingStartPoint(t *testing.T) {
+	branches := []Branch{
+		{Name: "main"},
+		{Name: "main", Remote: "origin"},
+		{Name: "fix", Remote: "origin"},
+		{Name: "shared", Remote: "origin"},
+		{Name: "shared", Remote: "upstream"},
+	}
+
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{name: "fix", want: "origin/fix", wantOK: true},
+		{name: "main"},   // exists locally
+		{name: "shared"}, // on two remotes
+		{name: "new"},
+	}
+
+	for _, tt := range tests {
+		got, ok := TrackingStartPoint(branches, tt.name)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("TrackingStartPoint(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
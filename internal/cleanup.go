@@ -0,0 +1,422 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// CleanupKind classifies an entry found by FindCleanupEntries.
+type CleanupKind int
+
+const (
+	// CleanupStale is a registered worktree whose directory is missing or
+	// no longer a valid worktree.
+	CleanupStale CleanupKind = iota
+	// CleanupOrphan is a directory inside the managed root that git does
+	// not know as a worktree.
+	CleanupOrphan
+	// CleanupOutside is a registered worktree outside the managed root.
+	CleanupOutside
+)
+
+// CleanupEntry is something wt clean offers to delete or re-register.
+type CleanupEntry struct {
+	Kind CleanupKind
+	Name string
+	Path string
+	// Branch and Head are empty for orphaned directories; Branch is
+	// DetachedBranch for detached worktrees.
+	Branch string
+	Head   string
+	// Reason explains what is wrong with the entry.
+	Reason string
+	// Repairable is set when the entry is one side of a worktree whose
+	// links broke because it or the repository moved. wt repair fixes
+	// those, so they are never deleted.
+	Repairable bool
+}
+
+// FindCleanupEntries returns the stale worktree entries, the unregistered
+// directories inside the managed root and the registered worktrees outside
+// of it. Locked worktrees are left alone, as git worktree prune does.
+func (wm *WorktreeManager) FindCleanupEntries(repoPath string) ([]CleanupEntry, error) {
+	if err := validatePath(repoPath); err != nil {
+		return nil, fmt.Errorf("invalid repository path: %w", err)
+	}
+
+	layout, err := LoadLayout(wm.runner, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	worktrees, err := wm.gitService.ListWorktrees()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	var entries []CleanupEntry
+	var registered []string
+	for _, wt := range worktrees {
+		registered = append(registered, filepath.Clean(wt.Path))
+		if wt.Locked || filepath.Clean(wt.Path) == layout.RepoPath {
+			continue
+		}
+		entry := CleanupEntry{Name: wt.Name(), Path: wt.Path, Branch: wt.Branch, Head: wt.Head}
+		switch {
+		case wt.Status == StatusStale:
+			entry.Kind = CleanupStale
+			entry.Reason = "registered, but not a valid worktree"
+			if _, err := os.Stat(wt.Path); os.IsNotExist(err) {
+				entry.Reason = "registered, but the directory is missing"
+			}
+		case !layout.IsManaged(wt.Path):
+			entry.Kind = CleanupOutside
+			entry.Reason = "outside the managed directory " + layout.Root()
+		default:
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	orphans, err := findOrphans(layout, layout.Root(), registered)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range orphans {
+		rel, _ := relativeTo(layout.Root(), path)
+		entries = append(entries, CleanupEntry{
+			Kind:   CleanupOrphan,
+			Name:   BranchToWorktreeName(filepath.ToSlash(rel)),
+			Path:   path,
+			Reason: "directory not registered with git",
+		})
+	}
+
+	if err := wm.markRepairable(repoPath, layout, entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// markRepairable flags the stale entries and orphaned directories that
+// belong to a worktree whose links are merely broken: a directory whose
+// .git file names existing administrative files, and an entry whose
+// administrative files such a directory names.
+func (wm *WorktreeManager) markRepairable(repoPath string, layout *Layout, entries []CleanupEntry) error {
+	if !slices.ContainsFunc(entries, func(e CleanupEntry) bool { return e.Kind != CleanupOutside }) {
+		return nil
+	}
+	commonDir, err := wm.gitCommonDir(repoPath)
+	if err != nil {
+		return err
+	}
+	admin, err := readAdminEntries(commonDir)
+	if err != nil {
+		return err
+	}
+
+	// Administrative files claimed by a directory on disk
+	claimed := make(map[string]bool)
+	for _, dir := range findLinkedDirs(layout, layout.Root(), 0) {
+		if adminDir, ok := linkedAdminDir(commonDir, dir); ok {
+			claimed[adminDir] = true
+		}
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		switch entry.Kind {
+		case CleanupOrphan:
+			_, entry.Repairable = linkedAdminDir(commonDir, entry.Path)
+		case CleanupStale:
+			for _, a := range admin {
+				if a.Worktree != filepath.Clean(entry.Path) {
+					continue
+				}
+				if _, ok := linkedAdminDir(commonDir, entry.Path); ok || claimed[a.Dir] {
+					entry.Repairable = true
+				}
+			}
+		}
+		if entry.Repairable {
+			entry.Reason = "links to the repository are broken, run wt repair"
+		}
+	}
+	return nil
+}
+
+// findOrphans returns the directories below dir that are neither a
+// registered worktree nor lead to one. Only paths the template could have
+// produced are returned, so nothing else that lives in the root is taken
+// for a worktree.
+func findOrphans(layout *Layout, dir string, registered []string) ([]string, error) {
+	children, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	var orphans []string
+	for _, child := range children {
+		if !child.IsDir() || child.Name() == ".git" {
+			continue
+		}
+		path := filepath.Join(dir, child.Name())
+		// The git directory of a bare clone may share the managed root
+		if path == layout.RepoPath {
+			continue
+		}
+
+		leads := false
+		known := false
+		for _, wt := range registered {
+			if wt == path {
+				known = true
+				break
+			}
+			if _, inside := relativeTo(path, wt); inside {
+				leads = true
+			}
+		}
+		switch {
+		case known:
+		case leads:
+			nested, err := findOrphans(layout, path, registered)
+			if err != nil {
+				return nil, err
+			}
+			orphans = append(orphans, nested...)
+		case layout.Matches(path):
+			orphans = append(orphans, path)
+		}
+	}
+	return orphans, nil
+}
+
+// DeleteCleanupEntry gets rid of entry: a stale entry loses only its
+// administrative files in the git directory, an orphaned directory is
+// deleted from disk, and a worktree outside the managed root is removed
+// with git worktree remove, which refuses if it has changes.
+func (wm *WorktreeManager) DeleteCleanupEntry(repoPath string, entry CleanupEntry) error {
+	if entry.Repairable {
+		return fmt.Errorf("%s only has broken links, run wt repair instead of deleting it", entry.Name)
+	}
+
+	switch entry.Kind {
+	case CleanupStale:
+		adminDir, err := wm.worktreeAdminDir(repoPath, entry.Path)
+		if err != nil {
+			return err
+		}
+		if err := os.RemoveAll(adminDir); err != nil {
+			return fmt.Errorf("failed to prune %s: %w", entry.Name, err)
+		}
+		return nil
+	case CleanupOrphan:
+		// Deleting a whole tree is only safe where wt puts worktrees
+		layout, err := LoadLayout(wm.runner, repoPath)
+		if err != nil {
+			return err
+		}
+		if !layout.Matches(entry.Path) {
+			return fmt.Errorf("refusing to delete %s: not a worktree directory of %s", entry.Path, layout.Root())
+		}
+		if err := os.RemoveAll(entry.Path); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", entry.Path, err)
+		}
+		return nil
+	default:
+		return wm.removeGitWorktree(repoPath, entry.Path, false)
+	}
+}
+
+// ReregisterCleanupEntry brings entry back under git and wt: a stale entry
+// is repaired, or checked out again if its directory is gone, an orphaned
+// directory is registered as the worktree of the branch that maps to it,
+// and a worktree outside the managed root is moved into it. It returns the
+// resulting worktree path.
+func (wm *WorktreeManager) ReregisterCleanupEntry(repoPath string, entry CleanupEntry) (string, error) {
+	switch entry.Kind {
+	case CleanupStale:
+		return entry.Path, wm.reregisterStale(repoPath, entry)
+	case CleanupOrphan:
+		return entry.Path, wm.registerOrphan(repoPath, entry)
+	default:
+		return wm.moveIntoRoot(repoPath, entry)
+	}
+}
+
+func (wm *WorktreeManager) reregisterStale(repoPath string, entry CleanupEntry) error {
+	if _, err := os.Stat(entry.Path); err == nil {
+		repairCmd := fmt.Sprintf("git -C %s worktree repair %s", shellescape(repoPath), shellescape(entry.Path))
+		if _, err := wm.runner.Run(repairCmd); err != nil {
+			return fmt.Errorf("failed to repair %s: %w", entry.Name, err)
+		}
+		return nil
+	}
+
+	// --force replaces the registration of the missing directory
+	gitCmd := fmt.Sprintf("git -C %s worktree add --force %s %s",
+		shellescape(repoPath),
+		shellescape(entry.Path),
+		shellescape(entry.Branch))
+	if entry.Branch == DetachedBranch {
+		gitCmd = fmt.Sprintf("git -C %s worktree add --force --detach %s %s",
+			shellescape(repoPath),
+			shellescape(entry.Path),
+			shellescape(entry.Head))
+	}
+	if _, err := wm.runner.Run(gitCmd); err != nil {
+		return fmt.Errorf("failed to check out %s again: %w", entry.Name, err)
+	}
+	return nil
+}
+
+// registerOrphan registers the directory of entry as a worktree without
+// touching its files. A directory that was moved by hand still points at
+// its administrative files and only needs a repair. Otherwise a worktree of
+// the matching branch is added without checkout and its .git file moved
+// into the directory, leaving any differences as uncommitted changes.
+func (wm *WorktreeManager) registerOrphan(repoPath string, entry CleanupEntry) error {
+	if gitdir, err := readGitFile(entry.Path); err == nil {
+		if _, err := os.Stat(gitdir); err == nil {
+			repairCmd := fmt.Sprintf("git -C %s worktree repair %s", shellescape(repoPath), shellescape(entry.Path))
+			if _, err := wm.runner.Run(repairCmd); err != nil {
+				return fmt.Errorf("failed to repair %s: %w", entry.Name, err)
+			}
+			return nil
+		}
+	}
+
+	branch, err := wm.branchForPath(repoPath, entry.Path)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp(filepath.Dir(entry.Path), "."+filepath.Base(entry.Path)+"-wt-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+	staging := filepath.Join(tmp, filepath.Base(entry.Path))
+
+	addCmd := fmt.Sprintf("git -C %s worktree add --no-checkout %s %s",
+		shellescape(repoPath),
+		shellescape(staging),
+		shellescape(branch))
+	if _, err := wm.runner.Run(addCmd); err != nil {
+		return fmt.Errorf("failed to register %s: %w", entry.Name, err)
+	}
+
+	if err := os.Rename(filepath.Join(staging, ".git"), filepath.Join(entry.Path, ".git")); err != nil {
+		_, _ = wm.runner.Run(fmt.Sprintf("git -C %s worktree remove --force %s", shellescape(repoPath), shellescape(staging)))
+		return fmt.Errorf("failed to link %s: %w", entry.Path, err)
+	}
+
+	repairCmd := fmt.Sprintf("git -C %s worktree repair %s", shellescape(repoPath), shellescape(entry.Path))
+	if _, err := wm.runner.Run(repairCmd); err != nil {
+		return fmt.Errorf("failed to repair %s: %w", entry.Name, err)
+	}
+	// Fill the index from HEAD so only real differences show as changes
+	resetCmd := fmt.Sprintf("git -C %s reset --quiet", shellescape(entry.Path))
+	if _, err := wm.runner.Run(resetCmd); err != nil {
+		return fmt.Errorf("failed to read the index of %s: %w", entry.Name, err)
+	}
+	return nil
+}
+
+// branchForPath returns the branch whose worktree path under the layout is
+// path.
+func (wm *WorktreeManager) branchForPath(repoPath, path string) (string, error) {
+	layout, err := LoadLayout(wm.runner, repoPath)
+	if err != nil {
+		return "", err
+	}
+	output, err := wm.runner.Run(fmt.Sprintf("git -C %s for-each-ref --format=%s refs/heads",
+		shellescape(repoPath),
+		shellescape("%(refname:short)")))
+	if err != nil {
+		return "", fmt.Errorf("failed to list branches: %w", err)
+	}
+	for _, branch := range strings.Fields(output) {
+		if candidate, err := layout.WorktreePath(branch); err == nil && candidate == filepath.Clean(path) {
+			return branch, nil
+		}
+	}
+	return "", fmt.Errorf("no branch has its worktree at %s, register it with git worktree add", path)
+}
+
+func (wm *WorktreeManager) moveIntoRoot(repoPath string, entry CleanupEntry) (string, error) {
+	if entry.Branch == DetachedBranch {
+		return "", fmt.Errorf("cannot choose a path for detached worktree %s, move it with git worktree move", entry.Name)
+	}
+	layout, err := LoadLayout(wm.runner, repoPath)
+	if err != nil {
+		return "", err
+	}
+	target, err := layout.WorktreePath(entry.Branch)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Lstat(target); err == nil {
+		return "", fmt.Errorf("cannot move %s: %s already exists", entry.Name, target)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+	}
+
+	moveCmd := fmt.Sprintf("git -C %s worktree move %s %s",
+		shellescape(repoPath),
+		shellescape(entry.Path),
+		shellescape(target))
+	if _, err := wm.runner.Run(moveCmd); err != nil {
+		return "", fmt.Errorf("failed to move %s: %w", entry.Name, err)
+	}
+	return target, nil
+}
+
+// worktreeAdminDir returns the directory in the git directory that holds
+// the administrative files of the worktree at path.
+func (wm *WorktreeManager) worktreeAdminDir(repoPath, path string) (string, error) {
+	commonDir, err := wm.gitCommonDir(repoPath)
+	if err != nil {
+		return "", err
+	}
+	adminDirs, err := os.ReadDir(filepath.Join(commonDir, "worktrees"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read worktree administrative files: %w", err)
+	}
+	for _, dir := range adminDirs {
+		adminDir := filepath.Join(commonDir, "worktrees", dir.Name())
+		content, err := os.ReadFile(filepath.Join(adminDir, "gitdir"))
+		if err != nil {
+			continue
+		}
+		// gitdir holds the path of the worktree's .git file
+		if filepath.Dir(strings.TrimSpace(string(content))) == filepath.Clean(path) {
+			return adminDir, nil
+		}
+	}
+	return "", fmt.Errorf("no administrative files found for %s", path)
+}
+
+// readGitFile returns the git directory a worktree's .git file points to.
+func readGitFile(worktreePath string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(worktreePath, ".git"))
+	if err != nil {
+		return "", err
+	}
+	gitdir, ok := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir: ")
+	if !ok {
+		return "", fmt.Errorf("%s/.git is not a gitdir file", worktreePath)
+	}
+	if !filepath.IsAbs(gitdir) {
+		gitdir = filepath.Join(worktreePath, gitdir)
+	}
+	return gitdir, nil
+}
@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWorktreeManager_FindCleanupEntries(t *testing.T) {
+	repo := t.TempDir()
+	root := filepath.Join(repo, "worktrees")
+	for _, dir := range []string{"active", "orphan", "review/101", "review/old"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// moved is a worktree whose repository was renamed; its .git file
+	// still names the old location
+	commonDir := filepath.Join(repo, ".git")
+	if err := os.MkdirAll(filepath.Join(commonDir, "worktrees", "moved"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(commonDir, "worktrees", "moved", "gitdir"), []byte("/old/worktrees/moved/.git\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "moved"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "moved", ".git"), []byte("gitdir: /old/.git/worktrees/moved\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := &MockCommandRunner{outputs: map[string]string{
+		"git worktree list --porcelain": "worktree " + repo + "\nHEAD aaa\nbranch refs/heads/main\n\n" +
+			"worktree " + root + "/active\nHEAD bbb\nbranch refs/heads/active\n\n" +
+			"worktree " + root + "/review/101\nHEAD ccc\nbranch refs/heads/review/101\n\n" +
+			"worktree " + root + "/gone\nHEAD ddd\nbranch refs/heads/gone\n\n" +
+			"worktree " + root + "/usb\nHEAD eee\nbranch refs/heads/usb\nlocked\n\n" +
+			"worktree /elsewhere/hack\nHEAD fff\nbranch refs/heads/hack\n\n" +
+			"worktree /old/worktrees/moved\nHEAD 111\nbranch refs/heads/moved",
+		"git -C " + repo + " status --porcelain":                    "",
+		"git -C " + root + "/active status --porcelain":             "",
+		"git -C " + root + "/review/101 status --porcelain":         "",
+		"git -C /elsewhere/hack status --porcelain":                 "",
+		"git -C " + repo + " config --get " + PathTemplateConfigKey: "worktrees/{{.Branch}}\n",
+		"git -C " + repo + " rev-parse --git-common-dir":            ".git\n",
+	}}
+	manager := NewWorktreeManager(NewGitService(runner), runner)
+
+	entries, err := manager.FindCleanupEntries(repo)
+	if err != nil {
+		t.Fatalf("FindCleanupEntries() error = %v (commands: %v)", err, runner.GetCommands())
+	}
+
+	want := []CleanupEntry{
+		{Kind: CleanupStale, Name: "gone", Path: root + "/gone", Branch: "gone", Head: "ddd", Reason: "registered, but the directory is missing"},
+		{Kind: CleanupOutside, Name: "hack", Path: "/elsewhere/hack", Branch: "hack", Head: "fff", Reason: "outside the managed directory " + root},
+		{Kind: CleanupStale, Name: "moved", Path: "/old/worktrees/moved", Branch: "moved", Head: "111", Reason: "links to the repository are broken, run wt repair", Repairable: true},
+		{Kind: CleanupOrphan, Name: "moved", Path: root + "/moved", Reason: "links to the repository are broken, run wt repair", Repairable: true},
+		{Kind: CleanupOrphan, Name: "orphan", Path: root + "/orphan", Reason: "directory not registered with git"},
+		{Kind: CleanupOrphan, Name: "review-old", Path: root + "/review/old", Reason: "directory not registered with git"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("FindCleanupEntries() =\n%+v\nwant\n%+v", entries, want)
+	}
+}
+
+func TestWorktreeManager_DeleteCleanupEntry(t *testing.T) {
+	commonDir := t.TempDir()
+	adminDir := func(id, worktree string) string {
+		dir := filepath.Join(commonDir, "worktrees", id)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "gitdir"), []byte(worktree+"/.git\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	stale := adminDir("gone", "/repo/worktrees/gone")
+	other := adminDir("active", "/repo/worktrees/active")
+
+	runner := &MockCommandRunner{outputs: map[string]string{
+		"git -C /repo rev-parse --git-common-dir": commonDir + "\n",
+	}}
+	manager := NewWorktreeManager(NewGitService(runner), runner)
+
+	entry := CleanupEntry{Kind: CleanupStale, Name: "gone", Path: "/repo/worktrees/gone"}
+	if err := manager.DeleteCleanupEntry("/repo", entry); err != nil {
+		t.Fatalf("DeleteCleanupEntry() error = %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("administrative files of the stale entry remain (stat error %v)", err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("administrative files of another worktree were removed: %v", err)
+	}
+}
+
+// A template that put worktrees beside the repository would make every
+// sibling directory look orphaned; nothing outside a root of the
+// repository's own may ever be deleted.
+func TestWorktreeManager_CleanupSiblingTemplate(t *testing.T) {
+	parent := t.TempDir()
+	repo := filepath.Join(parent, "app")
+	sibling := filepath.Join(parent, "other-project")
+	orphan := filepath.Join(repo, "worktrees", "orphan")
+	for _, dir := range []string{sibling, orphan} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	configCmd := "git -C " + repo + " config --get " + PathTemplateConfigKey
+	runner := &MockCommandRunner{outputs: map[string]string{
+		"git worktree list --porcelain":          "worktree " + repo + "\nHEAD aaa\nbranch refs/heads/main",
+		"git -C " + repo + " status --porcelain": "",
+		configCmd:                                "{{.RepoParent}}/{{.RepoName}}-{{.Name}}\n",
+	}}
+	manager := NewWorktreeManager(NewGitService(runner), runner)
+
+	if entries, err := manager.FindCleanupEntries(repo); err == nil {
+		t.Errorf("FindCleanupEntries() = %+v, want the template rejected", entries)
+	}
+	siblingEntry := CleanupEntry{Kind: CleanupOrphan, Name: "other-project", Path: sibling}
+	if err := manager.DeleteCleanupEntry(repo, siblingEntry); err == nil {
+		t.Error("DeleteCleanupEntry() deleted a sibling of the repository")
+	}
+
+	// With the default layout, only directories inside worktrees/ go
+	runner.outputs[configCmd] = DefaultPathTemplate + "\n"
+	if err := manager.DeleteCleanupEntry(repo, siblingEntry); err == nil {
+		t.Error("DeleteCleanupEntry() deleted a directory outside the managed root")
+	}
+	if _, err := os.Stat(sibling); err != nil {
+		t.Fatalf("sibling directory is gone: %v", err)
+	}
+	if err := manager.DeleteCleanupEntry(repo, CleanupEntry{Kind: CleanupOrphan, Name: "orphan", Path: orphan}); err != nil {
+		t.Fatalf("DeleteCleanupEntry() error = %v", err)
+	}
+	if _, err := os.Stat(orphan); !os.IsNotExist(err) {
+		t.Errorf("orphan was not deleted (stat error %v)", err)
+	}
+}
@@ -0,0 +1,175 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// bareCloneDir is where Clone puts the git directory of a bare clone.
+	bareCloneDir = ".bare"
+
+	// barePathTemplate places worktrees of a bare clone next to its git
+	// directory, so the clone directory holds one directory per branch.
+	barePathTemplate = "{{.RepoParent}}/{{.Name}}"
+)
+
+// CloneResult describes a repository created by Clone.
+type CloneResult struct {
+	// RepoPath is the main worktree, or the git directory of a bare clone.
+	RepoPath string
+	// WorktreePath is the worktree holding the default branch.
+	WorktreePath  string
+	DefaultBranch string
+}
+
+// Clone clones source into dir and prepares it for wt.
+//
+// A regular clone is configured with the default layout. A bare clone puts
+// the git directory in dir/.bare, points dir/.git at it, configures the
+// fetch refspec a bare clone lacks and adds a worktree for the default
+// branch. If any step fails, a directory created by Clone is removed again.
+func (wm *WorktreeManager) Clone(source, dir string, bare bool) (*CloneResult, error) {
+	if source == "" {
+		return nil, fmt.Errorf("clone source cannot be empty")
+	}
+	if strings.HasPrefix(source, "-") {
+		return nil, fmt.Errorf("clone source cannot start with dash: %q", source)
+	}
+
+	if err := validatePath(dir); err != nil {
+		return nil, fmt.Errorf("invalid clone directory: %w", err)
+	}
+
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("destination %s already exists and is not empty", dir)
+	}
+	_, statErr := os.Stat(dir)
+	createdDir := os.IsNotExist(statErr)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	var result *CloneResult
+	var err error
+	if bare {
+		result, err = wm.cloneBare(source, dir)
+	} else {
+		result, err = wm.cloneRegular(source, dir)
+	}
+	if err != nil {
+		if createdDir {
+			if rmErr := os.RemoveAll(dir); rmErr != nil {
+				return nil, fmt.Errorf("%w (cleanup of %s failed: %v)", err, dir, rmErr)
+			}
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (wm *WorktreeManager) cloneRegular(source, dir string) (*CloneResult, error) {
+	cloneCmd := fmt.Sprintf("git clone %s %s", shellescape(source), shellescape(dir))
+	if _, err := wm.runner.Run(cloneCmd); err != nil {
+		return nil, fmt.Errorf("git clone failed: %w", err)
+	}
+
+	if err := wm.setConfig(dir, PathTemplateConfigKey, DefaultPathTemplate); err != nil {
+		return nil, err
+	}
+
+	branch, err := wm.headBranch(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CloneResult{RepoPath: dir, WorktreePath: dir, DefaultBranch: branch}, nil
+}
+
+func (wm *WorktreeManager) cloneBare(source, dir string) (*CloneResult, error) {
+	gitDir := filepath.Join(dir, bareCloneDir)
+
+	cloneCmd := fmt.Sprintf("git clone --bare %s %s", shellescape(source), shellescape(gitDir))
+	if _, err := wm.runner.Run(cloneCmd); err != nil {
+		return nil, fmt.Errorf("git clone failed: %w", err)
+	}
+
+	// Let git commands run from the clone directory itself
+	if err := os.WriteFile(filepath.Join(dir, ".git"), []byte("gitdir: ./"+bareCloneDir+"\n"), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write .git file: %w", err)
+	}
+
+	// Bare clones map remote branches straight onto local ones and have no
+	// fetch refspec, so remote-tracking branches would never appear.
+	if err := wm.setConfig(gitDir, "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*"); err != nil {
+		return nil, err
+	}
+	fetchCmd := fmt.Sprintf("git -C %s fetch origin", shellescape(gitDir))
+	if _, err := wm.runner.Run(fetchCmd); err != nil {
+		return nil, fmt.Errorf("git fetch failed: %w", err)
+	}
+
+	if err := wm.setConfig(gitDir, PathTemplateConfigKey, barePathTemplate); err != nil {
+		return nil, err
+	}
+
+	branch, err := wm.headBranch(gitDir)
+	if err != nil {
+		return nil, err
+	}
+
+	upstreamCmd := fmt.Sprintf("git -C %s branch --set-upstream-to=%s %s",
+		shellescape(gitDir),
+		shellescape("origin/"+branch),
+		shellescape(branch))
+	if _, err := wm.runner.Run(upstreamCmd); err != nil {
+		return nil, fmt.Errorf("failed to set upstream of %s: %w", branch, err)
+	}
+
+	worktreePath, err := wm.AddWorktree(gitDir, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CloneResult{RepoPath: gitDir, WorktreePath: worktreePath, DefaultBranch: branch}, nil
+}
+
+func (wm *WorktreeManager) setConfig(repoPath, key, value string) error {
+	configCmd := fmt.Sprintf("git -C %s config %s %s",
+		shellescape(repoPath),
+		shellescape(key),
+		shellescape(value))
+	if _, err := wm.runner.Run(configCmd); err != nil {
+		return fmt.Errorf("failed to set %s: %w", key, err)
+	}
+	return nil
+}
+
+func (wm *WorktreeManager) headBranch(repoPath string) (string, error) {
+	output, err := wm.runner.Run(fmt.Sprintf("git -C %s symbolic-ref --short HEAD", shellescape(repoPath)))
+	if err != nil {
+		return "", fmt.Errorf("failed to determine default branch: %w", err)
+	}
+	branch := strings.TrimSpace(output)
+	if branch == "" {
+		return "", fmt.Errorf("failed to determine default branch: HEAD is not a branch")
+	}
+	return branch, nil
+}
+
+// CloneDirName returns the directory git would clone source into: the last
+// path component without a trailing .git.
+func CloneDirName(source string) string {
+	name := strings.TrimRight(source, "/")
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSuffix(name, ".git")
+	if name == "" || name == "." || name == ".." {
+		return ""
+	}
+	return name
+}
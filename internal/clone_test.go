@@ -0,0 +1,200 @@
+package internal
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorktreeManager_Clone(t *testing.T) {
+	t.Run("regular clone", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "app")
+		mockRunner := &MockCommandRunner{outputs: map[string]string{
+			"git clone /src/app " + dir:                                       "",
+			"git -C " + dir + " config wt.pathTemplate 'worktrees/{{.Name}}'": "",
+			"git -C " + dir + " symbolic-ref --short HEAD":                    "main\n",
+		}}
+		manager := NewWorktreeManager(NewGitService(mockRunner), mockRunner)
+
+		result, err := manager.Clone("/src/app", dir, false)
+		if err != nil {
+			t.Fatalf("Clone() error = %v (commands: %v)", err, mockRunner.GetCommands())
+		}
+		want := CloneResult{RepoPath: dir, WorktreePath: dir, DefaultBranch: "main"}
+		if *result != want {
+			t.Errorf("Clone() = %+v, want %+v", *result, want)
+		}
+	})
+
+	t.Run("bare clone", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "app")
+		gitDir := filepath.Join(dir, ".bare")
+		worktreePath := filepath.Join(dir, "main")
+
+		mockRunner := &MockCommandRunner{outputs: map[string]string{
+			"git clone --bare /src/app " + gitDir:                                                  "",
+			"git -C " + gitDir + " config remote.origin.fetch +refs/heads/*:refs/remotes/origin/*": "",
+			"git -C " + gitDir + " fetch origin":                                                   "",
+			"git -C " + gitDir + " config wt.pathTemplate '{{.RepoParent}}/{{.Name}}'":             "",
+			"git -C " + gitDir + " symbolic-ref --short HEAD":                                      "main\n",
+			"git -C " + gitDir + " branch --set-upstream-to=origin/main main":                      "",
+			"git -C " + gitDir + " rev-parse --is-bare-repository":                                 "true\n",
+			"git -C " + gitDir + " config --get wt.pathTemplate":                                   "{{.RepoParent}}/{{.Name}}\n",
+			"git -C " + gitDir + " worktree add " + worktreePath + " main":                         "",
+		}}
+		manager := NewWorktreeManager(NewGitService(mockRunner), mockRunner)
+
+		result, err := manager.Clone("/src/app", dir, true)
+		if err != nil {
+			t.Fatalf("Clone() error = %v (commands: %v)", err, mockRunner.GetCommands())
+		}
+		want := CloneResult{RepoPath: gitDir, WorktreePath: worktreePath, DefaultBranch: "main"}
+		if *result != want {
+			t.Errorf("Clone() = %+v, want %+v", *result, want)
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, ".git"))
+		if err != nil {
+			t.Fatalf("failed to read .git file: %v", err)
+		}
+		if string(content) != "gitdir: ./.bare\n" {
+			t.Errorf(".git = %q, want %q", content, "gitdir: ./.bare\n")
+		}
+	})
+
+	t.Run("failure removes created directory", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "app")
+		gitDir := filepath.Join(dir, ".bare")
+		mockRunner := &MockCommandRunner{outputs: map[string]string{
+			"git clone --bare /src/app " + gitDir: "",
+		}}
+		manager := NewWorktreeManager(NewGitService(mockRunner), mockRunner)
+
+		if _, err := manager.Clone("/src/app", dir, true); err == nil {
+			t.Fatal("Clone() expected error, got nil")
+		}
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("clone directory should have been removed, stat error = %v", err)
+		}
+	})
+
+	t.Run("refuses non-empty destination", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "file"), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		mockRunner := &MockCommandRunner{outputs: map[string]string{}}
+		manager := NewWorktreeManager(NewGitService(mockRunner), mockRunner)
+
+		_, err := manager.Clone("/src/app", dir, false)
+		if err == nil || !strings.Contains(err.Error(), "not empty") {
+			t.Errorf("Clone() error = %v, want error about non-empty destination", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "file")); err != nil {
+			t.Errorf("existing destination content must be kept: %v", err)
+		}
+	})
+}
+
+// TestWorktreeManager_Clone_Git clones a real repository and checks what a
+// later fetch, push and wt list rely on.
+func TestWorktreeManager_Clone_Git(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	git := func(t *testing.T, dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test User", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test User", "GIT_COMMITTER_EMAIL=test@example.com")
+		output, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	tempDir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := filepath.Join(tempDir, "src")
+	git(t, tempDir, "init", "-q", "-b", "trunk", source)
+	git(t, source, "commit", "-q", "--allow-empty", "-m", "Initial commit")
+	git(t, source, "branch", "feature")
+
+	for _, bare := range []bool{false, true} {
+		name := "regular clone"
+		if bare {
+			name = "bare clone"
+		}
+		t.Run(name, func(t *testing.T) {
+			runner := NewExecCommandRunner()
+			manager := NewWorktreeManager(NewGitService(runner), runner)
+			dir := filepath.Join(tempDir, strings.ReplaceAll(name, " ", "-"))
+
+			result, err := manager.Clone(source, dir, bare)
+			if err != nil {
+				t.Fatalf("Clone() error = %v", err)
+			}
+			if result.DefaultBranch != "trunk" {
+				t.Errorf("DefaultBranch = %q, want trunk", result.DefaultBranch)
+			}
+
+			if got := git(t, dir, "config", "remote.origin.fetch"); got != "+refs/heads/*:refs/remotes/origin/*" {
+				t.Errorf("remote.origin.fetch = %q", got)
+			}
+			if got := git(t, dir, "rev-parse", "--abbrev-ref", "trunk@{upstream}"); got != "origin/trunk" {
+				t.Errorf("upstream of trunk = %q, want origin/trunk", got)
+			}
+			if got := git(t, dir, "rev-parse", "--verify", "--quiet", "refs/remotes/origin/feature"); got == "" {
+				t.Error("origin/feature was not fetched")
+			}
+
+			t.Chdir(dir)
+			worktrees, err := manager.gitService.ListWorktrees()
+			if err != nil {
+				t.Fatalf("ListWorktrees() error = %v", err)
+			}
+			var found *Worktree
+			for i := range worktrees {
+				if worktrees[i].Path == result.WorktreePath {
+					found = &worktrees[i]
+				}
+			}
+			if found == nil || found.Branch != "trunk" {
+				t.Fatalf("ListWorktrees() = %+v, want %s on trunk", worktrees, result.WorktreePath)
+			}
+			if bare && found.Name() != "trunk" {
+				t.Errorf("Name() = %q, want trunk", found.Name())
+			}
+		})
+	}
+}
+
+func TestCloneDirName(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{source: "/src/app", want: "app"},
+		{source: "/src/app.git", want: "app"},
+		{source: "/src/app/", want: "app"},
+		{source: "https://example.com/team/app.git", want: "app"},
+		{source: "git@example.com:team/app.git", want: "app"},
+		{source: "git@example.com:app.git", want: "app"},
+		{source: "/", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			if got := CloneDirName(tt.source); got != tt.want {
+				t.Errorf("CloneDirName(%q) = %q, want %q", tt.source, got, tt.want)
+			}
+		})
+	}
+}
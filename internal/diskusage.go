@@ -0,0 +1,412 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SizeCacheTTLConfigKey is the git config key setting how long measured
+// sizes are reused by wt list --size, as a Go duration such as "30m".
+const SizeCacheTTLConfigKey = "wt.sizeCacheTTL"
+
+// DefaultSizeCacheTTL is how long measured sizes are reused without
+// configuration.
+const DefaultSizeCacheTTL = time.Hour
+
+// DiskUsage is the size of the files in a worktree, split by how git
+// treats them. Sizes are in bytes.
+type DiskUsage struct {
+	Name      string    `json:"name"`
+	Path      string    `json:"path"`
+	Tracked   int64     `json:"tracked"`
+	Untracked int64     `json:"untracked"`
+	Ignored   int64     `json:"ignored"`
+	Measured  time.Time `json:"measured"`
+}
+
+// Total returns the size of all files in the worktree.
+func (u DiskUsage) Total() int64 {
+	return u.Tracked + u.Untracked + u.Ignored
+}
+
+type fileClass int
+
+const (
+	classUnknown fileClass = iota
+	classTracked
+	classUntracked
+	classIgnored
+)
+
+// fileClasses tells which files of a worktree git tracks and which it
+// reports as untracked; everything else is ignored.
+type fileClasses struct {
+	tracked   map[string]bool
+	untracked map[string]bool
+	// parents holds every directory containing a tracked or untracked
+	// file. Anything below other directories is ignored as a whole.
+	parents map[string]bool
+}
+
+// classify returns the class of the file or directory rel, a slash
+// separated path relative to the worktree. Directories only get a class
+// when everything below them shares it.
+func (c *fileClasses) classify(rel string, dir bool) fileClass {
+	switch {
+	// A submodule is tracked as a single entry, and git lists an untracked
+	// nested repository as a single directory
+	case c.tracked[rel]:
+		return classTracked
+	case c.untracked[rel] || (dir && c.untracked[rel+"/"]):
+		return classUntracked
+	case dir && c.parents[rel]:
+		return classUnknown
+	default:
+		return classIgnored
+	}
+}
+
+// MeasureDiskUsage measures the worktrees of repoPath, or only those
+// named, and returns them largest first. Directories are walked
+// concurrently. The sizes are cached for CachedDiskUsage.
+func (wm *WorktreeManager) MeasureDiskUsage(repoPath string, names []string) ([]DiskUsage, error) {
+	worktrees, err := wm.measurableWorktrees(names)
+	if err != nil {
+		return nil, err
+	}
+	usages, err := wm.measure(worktrees)
+	if err != nil {
+		return nil, err
+	}
+	wm.storeSizes(repoPath, usages)
+	sortBySize(usages)
+	return usages, nil
+}
+
+// CachedDiskUsage returns the sizes of the worktrees of repoPath by path,
+// reusing sizes measured within wt.sizeCacheTTL and measuring the rest.
+func (wm *WorktreeManager) CachedDiskUsage(repoPath string) (map[string]DiskUsage, error) {
+	worktrees, err := wm.measurableWorktrees(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[string]DiskUsage, len(worktrees))
+	cache, _ := wm.readSizeCache(repoPath)
+	ttl := wm.sizeCacheTTL(repoPath)
+	var missing []Worktree
+	for _, wt := range worktrees {
+		if usage, ok := cache[wt.Path]; ok && time.Since(usage.Measured) < ttl {
+			sizes[wt.Path] = usage
+			continue
+		}
+		missing = append(missing, wt)
+	}
+	if len(missing) == 0 {
+		return sizes, nil
+	}
+
+	measured, err := wm.measure(missing)
+	if err != nil {
+		return nil, err
+	}
+	wm.storeSizes(repoPath, measured)
+	for _, usage := range measured {
+		sizes[usage.Path] = usage
+	}
+	return sizes, nil
+}
+
+// measurableWorktrees returns the worktrees named, or all of them, whose
+// directories still exist.
+func (wm *WorktreeManager) measurableWorktrees(names []string) ([]Worktree, error) {
+	worktrees, err := wm.gitService.ListWorktrees()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	var found []Worktree
+	for _, name := range names {
+		i := slices.IndexFunc(worktrees, func(wt Worktree) bool { return wt.Name() == name })
+		if i < 0 {
+			return nil, fmt.Errorf("worktree %q not found", name)
+		}
+		found = append(found, worktrees[i])
+	}
+	if len(names) == 0 {
+		found = worktrees
+	}
+
+	var existing []Worktree
+	for _, wt := range found {
+		if info, err := os.Stat(wt.Path); err == nil && info.IsDir() {
+			existing = append(existing, wt)
+		}
+	}
+	return existing, nil
+}
+
+// measure asks git how it treats the files of each worktree, then walks
+// all worktrees at once. Worktrees nested in another, such as those below
+// the main worktree, are only counted for themselves.
+func (wm *WorktreeManager) measure(worktrees []Worktree) ([]DiskUsage, error) {
+	classes := make([]*fileClasses, len(worktrees))
+	for i, wt := range worktrees {
+		c, err := wm.fileClasses(wt.Path)
+		if err != nil {
+			return nil, err
+		}
+		classes[i] = c
+	}
+
+	skip := make(map[string]bool, len(worktrees))
+	for _, wt := range worktrees {
+		skip[filepath.Clean(wt.Path)] = true
+	}
+
+	walker := &sizeWalker{tokens: make(chan struct{}, runtime.NumCPU()), skip: skip}
+	usages := make([]DiskUsage, len(worktrees))
+	counters := make([]sizeCounters, len(worktrees))
+	var wg sync.WaitGroup
+	for i, wt := range worktrees {
+		wg.Add(1)
+		walker.tokens <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-walker.tokens }()
+			walker.walk(&wg, filepath.Clean(wt.Path), "", classUnknown, classes[i], &counters[i])
+		}()
+	}
+	wg.Wait()
+
+	measured := time.Now()
+	for i, wt := range worktrees {
+		usages[i] = DiskUsage{
+			Name:      wt.Name(),
+			Path:      wt.Path,
+			Tracked:   counters[i].tracked.Load(),
+			Untracked: counters[i].untracked.Load(),
+			Ignored:   counters[i].ignored.Load(),
+			Measured:  measured,
+		}
+	}
+	return usages, nil
+}
+
+func (wm *WorktreeManager) fileClasses(path string) (*fileClasses, error) {
+	trackedCmd := fmt.Sprintf("git -C %s ls-files -z", shellescape(path))
+	tracked, err := wm.runner.Run(trackedCmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tracked files of %s: %w", path, err)
+	}
+	untrackedCmd := fmt.Sprintf("git -C %s ls-files -z --others --exclude-standard", shellescape(path))
+	untracked, err := wm.runner.Run(untrackedCmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files of %s: %w", path, err)
+	}
+
+	c := &fileClasses{
+		tracked:   make(map[string]bool),
+		untracked: make(map[string]bool),
+		parents:   make(map[string]bool),
+	}
+	c.add(c.tracked, tracked)
+	c.add(c.untracked, untracked)
+	return c, nil
+}
+
+// add records the NUL separated files of output in set.
+func (c *fileClasses) add(set map[string]bool, output string) {
+	for _, file := range strings.Split(output, "\x00") {
+		if file == "" {
+			continue
+		}
+		set[file] = true
+		for dir := strings.TrimSuffix(file, "/"); strings.Contains(dir, "/"); {
+			dir = dir[:strings.LastIndex(dir, "/")]
+			c.parents[dir] = true
+		}
+	}
+}
+
+type sizeCounters struct {
+	tracked, untracked, ignored atomic.Int64
+}
+
+func (s *sizeCounters) add(class fileClass, size int64) {
+	switch class {
+	case classTracked:
+		s.tracked.Add(size)
+	case classUntracked:
+		s.untracked.Add(size)
+	default:
+		s.ignored.Add(size)
+	}
+}
+
+// sizeWalker adds up file sizes below a directory. A subdirectory is
+// handed to a new goroutine when a token is free and walked in place
+// otherwise, so walking never waits on other walks.
+type sizeWalker struct {
+	tokens chan struct{}
+	// skip holds the worktree roots, which are only walked for themselves.
+	skip map[string]bool
+}
+
+// walk adds the files below dir to counters. rel is dir relative to its
+// worktree and class is its class when everything below it shares one.
+func (w *sizeWalker) walk(wg *sync.WaitGroup, dir, rel string, class fileClass, classes *fileClasses, counters *sizeCounters) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		// Unreadable directories are left out rather than failing the report
+		return
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		childRel := entry.Name()
+		if rel != "" {
+			childRel = rel + "/" + entry.Name()
+		}
+		if rel == "" && entry.Name() == ".git" {
+			continue
+		}
+
+		childClass := class
+		if childClass == classUnknown {
+			childClass = classes.classify(childRel, entry.IsDir())
+		}
+
+		if entry.IsDir() {
+			if w.skip[path] {
+				continue
+			}
+			select {
+			case w.tokens <- struct{}{}:
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer func() { <-w.tokens }()
+					w.walk(wg, path, childRel, childClass, classes, counters)
+				}()
+			default:
+				w.walk(wg, path, childRel, childClass, classes, counters)
+			}
+			continue
+		}
+
+		// Symbolic links are counted themselves, not followed
+		info, err := entry.Info()
+		if err != nil || !(info.Mode().IsRegular() || info.Mode()&fs.ModeSymlink != 0) {
+			continue
+		}
+		if childClass == classUnknown {
+			childClass = classIgnored
+		}
+		counters.add(childClass, info.Size())
+	}
+}
+
+func sortBySize(usages []DiskUsage) {
+	slices.SortStableFunc(usages, func(a, b DiskUsage) int {
+		switch {
+		case a.Total() > b.Total():
+			return -1
+		case a.Total() < b.Total():
+			return 1
+		default:
+			return strings.Compare(a.Name, b.Name)
+		}
+	})
+}
+
+func (wm *WorktreeManager) sizeCacheTTL(repoPath string) time.Duration {
+	configCmd := fmt.Sprintf("git -C %s config --get %s", shellescape(repoPath), SizeCacheTTLConfigKey)
+	if output, err := wm.runner.Run(configCmd); err == nil {
+		if ttl, err := time.ParseDuration(strings.TrimSpace(output)); err == nil && ttl >= 0 {
+			return ttl
+		}
+	}
+	return DefaultSizeCacheTTL
+}
+
+// sizeCachePath returns the file caching the sizes of the worktrees of
+// repoPath, shared by all of them.
+func (wm *WorktreeManager) sizeCachePath(repoPath string) (string, error) {
+	commonDir, err := wm.gitCommonDir(repoPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(commonDir, "wt", "sizes.json"), nil
+}
+
+func (wm *WorktreeManager) readSizeCache(repoPath string) (map[string]DiskUsage, error) {
+	path, err := wm.sizeCachePath(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var usages []DiskUsage
+	if err := json.Unmarshal(content, &usages); err != nil {
+		return nil, err
+	}
+	cache := make(map[string]DiskUsage, len(usages))
+	for _, usage := range usages {
+		cache[usage.Path] = usage
+	}
+	return cache, nil
+}
+
+// storeSizes merges usages into the size cache. The cache only saves work,
+// so failing to write it is not an error.
+func (wm *WorktreeManager) storeSizes(repoPath string, usages []DiskUsage) {
+	path, err := wm.sizeCachePath(repoPath)
+	if err != nil {
+		return
+	}
+	cache, err := wm.readSizeCache(repoPath)
+	if err != nil || cache == nil {
+		cache = make(map[string]DiskUsage)
+	}
+	for _, usage := range usages {
+		cache[usage.Path] = usage
+	}
+
+	// Drop worktrees that no longer exist
+	var entries []DiskUsage
+	for _, usage := range cache {
+		if _, err := os.Stat(usage.Path); err == nil {
+			entries = append(entries, usage)
+		}
+	}
+	slices.SortFunc(entries, func(a, b DiskUsage) int { return strings.Compare(a.Path, b.Path) })
+
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil || os.MkdirAll(filepath.Dir(path), 0o755) != nil {
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(content, '\n'), 0o644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+	}
+}
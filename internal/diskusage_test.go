@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorktreeManager_MeasureDiskUsage(t *testing.T) {
+	repo := t.TempDir()
+	feature := filepath.Join(repo, "worktrees", "feature")
+	files := map[string]int{
+		// main worktree
+		"README.md":          10,
+		"notes.txt":          20,
+		"build/out.bin":      300,
+		"vendor/lib/keep.go": 40,
+		"vendor/lib/gen.o":   500,
+		"scratch/a.txt":      6,
+		".git/objects/pack":  9000,
+		// feature worktree, nested in the main one
+		"worktrees/feature/README.md":         11,
+		"worktrees/feature/node_modules/x.js": 2000,
+		"worktrees/feature/node_modules/y.js": 1000,
+		"worktrees/feature/.git":              30,
+		"worktrees/orphan/left-behind.txt":    7,
+	}
+	for name, size := range files {
+		path := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runner := &MockCommandRunner{outputs: map[string]string{
+		"git worktree list --porcelain": "worktree " + repo + "\nHEAD aaa\nbranch refs/heads/main\n\n" +
+			"worktree " + feature + "\nHEAD bbb\nbranch refs/heads/feature\n\n" +
+			"worktree " + repo + "/worktrees/gone\nHEAD ccc\nbranch refs/heads/gone",
+		"git -C " + repo + " status --porcelain":                         "",
+		"git -C " + feature + " status --porcelain":                      "",
+		"git -C " + repo + " rev-parse --git-common-dir":                 ".git\n",
+		"git -C " + repo + " ls-files -z":                                "README.md\x00vendor/lib/keep.go\x00",
+		"git -C " + repo + " ls-files -z --others --exclude-standard":    "notes.txt\x00scratch/\x00",
+		"git -C " + feature + " ls-files -z":                             "README.md\x00",
+		"git -C " + feature + " ls-files -z --others --exclude-standard": "",
+	}}
+	manager := NewWorktreeManager(NewGitService(runner), runner)
+
+	usages, err := manager.MeasureDiskUsage(repo, nil)
+	if err != nil {
+		t.Fatalf("MeasureDiskUsage() error = %v (commands: %v)", err, runner.GetCommands())
+	}
+
+	want := []DiskUsage{
+		{Name: "feature", Path: feature, Tracked: 11, Ignored: 3000},
+		{Name: "main", Path: repo, Tracked: 50, Untracked: 26, Ignored: 807},
+	}
+	if len(usages) != len(want) {
+		t.Fatalf("MeasureDiskUsage() = %+v, want %+v", usages, want)
+	}
+	for i, got := range usages {
+		w := want[i]
+		if got.Name != w.Name || got.Path != w.Path || got.Tracked != w.Tracked || got.Untracked != w.Untracked || got.Ignored != w.Ignored {
+			t.Errorf("MeasureDiskUsage()[%d] = %+v, want %+v", i, got, w)
+		}
+	}
+
+	// The sizes just measured are reused without asking git again
+	before := len(runner.GetCommands())
+	sizes, err := manager.CachedDiskUsage(repo)
+	if err != nil {
+		t.Fatalf("CachedDiskUsage() error = %v", err)
+	}
+	if sizes[feature].Total() != 3011 || sizes[repo].Total() != 883 {
+		t.Errorf("CachedDiskUsage() = %+v, want the measured sizes", sizes)
+	}
+	for _, cmd := range runner.GetCommands()[before:] {
+		if strings.Contains(cmd, "ls-files") {
+			t.Errorf("cached worktree measured again: %s", cmd)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package internal
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WorktreeFilter selects managed worktrees by name pattern and state.
+// A worktree is selected when it matches one of the patterns (or there are
+// none) and every filter that is set.
+type WorktreeFilter struct {
+	// Patterns are exact worktree names or globs in path.Match syntax.
+	Patterns []string
+	// Branch is a glob the branch name must match, such as "review/*".
+	Branch string
+	// Merged selects worktrees whose HEAD is contained in the base branch.
+	Merged bool
+	// OlderThan selects worktrees whose HEAD commit is older than this.
+	OlderThan time.Duration
+	// CleanOnly selects worktrees without uncommitted changes.
+	CleanOnly bool
+
+	now func() time.Time
+}
+
+// IsGlob reports whether pattern contains glob metacharacters.
+func IsGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// Expands reports whether the filter selects worktrees by anything other
+// than exact names, so the resulting list is worth confirming.
+func (f WorktreeFilter) Expands() bool {
+	for _, p := range f.Patterns {
+		if IsGlob(p) {
+			return true
+		}
+	}
+	return f.Branch != "" || f.Merged || f.OlderThan > 0 || f.CleanOnly
+}
+
+// SelectWorktrees returns the names of the worktrees selected by f, in the
+// order git lists them. Exact names are kept even if no such worktree
+// exists so that removal reports them; only managed worktrees other than
+// the main one are candidates for patterns.
+func (wm *WorktreeManager) SelectWorktrees(repoPath string, f WorktreeFilter) ([]string, error) {
+	if len(f.Patterns) == 0 && !f.Expands() {
+		return nil, fmt.Errorf("at least one worktree name, pattern or filter is required")
+	}
+	for _, p := range append([]string{f.Branch}, f.Patterns...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+	}
+
+	layout, err := LoadLayout(wm.runner, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	worktrees, err := wm.gitService.ListWorktrees()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	var base string
+	if f.Merged {
+		if base, err = wm.baseBranch(repoPath); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now
+	if f.now != nil {
+		now = f.now
+	}
+
+	var selected []string
+	matched := make(map[string]bool)
+	for _, wt := range worktrees {
+		if wt.Bare || wt.Path == layout.RepoPath || !layout.IsManaged(wt.Path) {
+			continue
+		}
+
+		name := wt.Name()
+		pattern, ok := matchPatterns(f.Patterns, name)
+		if !ok {
+			continue
+		}
+		matched[pattern] = true
+
+		ok, err := wm.matchFilters(repoPath, wt, f, base, now())
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			selected = append(selected, name)
+		}
+	}
+
+	for _, p := range f.Patterns {
+		if matched[p] {
+			continue
+		}
+		if IsGlob(p) {
+			return nil, fmt.Errorf("pattern %q matches no worktree", p)
+		}
+		// Let the removal report the unknown name
+		selected = append(selected, p)
+	}
+
+	return selected, nil
+}
+
+// matchPatterns returns the first pattern name matches. Without patterns
+// every name matches.
+func matchPatterns(patterns []string, name string) (string, bool) {
+	if len(patterns) == 0 {
+		return "", true
+	}
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+func (wm *WorktreeManager) matchFilters(repoPath string, wt Worktree, f WorktreeFilter, base string, now time.Time) (bool, error) {
+	if f.CleanOnly && wt.Status != StatusClean {
+		return false, nil
+	}
+
+	if f.Branch != "" {
+		if wt.Detached() {
+			return false, nil
+		}
+		if ok, _ := path.Match(f.Branch, wt.Branch); !ok {
+			return false, nil
+		}
+	}
+
+	if f.Merged {
+		ancestorCmd := fmt.Sprintf("git -C %s merge-base --is-ancestor %s %s",
+			shellescape(repoPath),
+			shellescape(wt.Head),
+			shellescape("refs/heads/"+base))
+		// A non-ancestor exits 1; anything else is treated the same way
+		if _, err := wm.runner.Run(ancestorCmd); err != nil {
+			return false, nil
+		}
+		if atTip, err := wm.atBaseTip(repoPath, wt.Head, base); err != nil || atTip {
+			return false, err
+		}
+	}
+
+	if f.OlderThan > 0 {
+		logCmd := fmt.Sprintf("git -C %s log -1 --format=%%ct %s", shellescape(repoPath), shellescape(wt.Head))
+		output, err := wm.runner.Run(logCmd)
+		if err != nil {
+			return false, fmt.Errorf("failed to read commit time of %s: %w", wt.Name(), err)
+		}
+		seconds, err := strconv.ParseInt(strings.TrimSpace(output), 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid commit time %q for %s", strings.TrimSpace(output), wt.Name())
+		}
+		if now.Sub(time.Unix(seconds, 0)) < f.OlderThan {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// ParseAge parses an age such as "14d", "2w" or any time.ParseDuration
+// value like "36h".
+func ParseAge(s string) (time.Duration, error) {
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	}
+	for suffix, unit := range units {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil {
+				return 0, fmt.Errorf("invalid age %q", s)
+			}
+			return positiveAge(s, time.Duration(count)*unit)
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid age %q, use e.g. 14d, 2w or 36h", s)
+	}
+	return positiveAge(s, d)
+}
+
+// positiveAge rejects ages of zero or less, which would not filter
+// anything.
+func positiveAge(s string, d time.Duration) (time.Duration, error) {
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid age %q, it must be greater than zero", s)
+	}
+	return d, nil
+}
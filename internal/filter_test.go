@@ -0,0 +1,159 @@
+package internal
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWorktreeManager_SelectWorktrees(t *testing.T) {
+	now := time.Unix(2_000_000_000, 0)
+	day := int64(24 * 60 * 60)
+	unixTime := func(seconds int64) string { return strconv.FormatInt(seconds, 10) + "\n" }
+
+	listOutput := "worktree /repo\nHEAD aaa\nbranch refs/heads/main\n\n" +
+		"worktree /repo/worktrees/review-101\nHEAD bbb\nbranch refs/heads/review/101\n\n" +
+		"worktree /repo/worktrees/review-102\nHEAD ccc\nbranch refs/heads/review/102\n\n" +
+		"worktree /repo/worktrees/spike-cache\nHEAD ddd\nbranch refs/heads/spike/cache\n\n" +
+		"worktree /repo/worktrees/review-103\nHEAD aaa\nbranch refs/heads/review/103\n\n" +
+		"worktree /elsewhere/review-old\nHEAD eee\nbranch refs/heads/review/old"
+
+	outputs := map[string]string{
+		"git worktree list --porcelain":                         listOutput,
+		"git -C /repo status --porcelain":                       "",
+		"git -C /repo/worktrees/review-101 status --porcelain":  "",
+		"git -C /repo/worktrees/review-102 status --porcelain":  " M file.go\n",
+		"git -C /repo/worktrees/spike-cache status --porcelain": "",
+		"git -C /repo/worktrees/review-103 status --porcelain":  " M new.go\n",
+		"git -C /elsewhere/review-old status --porcelain":       "",
+		"git -C /repo symbolic-ref --short HEAD":                "main\n",
+		// Only review-101 and spike-cache are merged; review-103 was just
+		// branched off the tip of main
+		"git -C /repo merge-base --is-ancestor bbb refs/heads/main": "",
+		"git -C /repo merge-base --is-ancestor ddd refs/heads/main": "",
+		"git -C /repo merge-base --is-ancestor aaa refs/heads/main": "",
+		"git -C /repo rev-parse refs/heads/main":                    "aaa\n",
+		"git -C /repo log -1 --format=%ct aaa":                      unixTime(2_000_000_000 - 1*day),
+		"git -C /repo log -1 --format=%ct bbb":                      unixTime(2_000_000_000 - 30*day),
+		"git -C /repo log -1 --format=%ct ccc":                      unixTime(2_000_000_000 - 20*day),
+		"git -C /repo log -1 --format=%ct ddd":                      unixTime(2_000_000_000 - 2*day),
+	}
+
+	tests := []struct {
+		name    string
+		filter  WorktreeFilter
+		want    []string
+		wantErr string
+	}{
+		{
+			name:   "glob on names skips unmanaged worktrees",
+			filter: WorktreeFilter{Patterns: []string{"review-*"}},
+			want:   []string{"review-101", "review-102", "review-103"},
+		},
+		{
+			name:   "exact names pass through",
+			filter: WorktreeFilter{Patterns: []string{"spike-*", "missing"}},
+			want:   []string{"spike-cache", "missing"},
+		},
+		{
+			name:   "branch glob",
+			filter: WorktreeFilter{Branch: "spike/*"},
+			want:   []string{"spike-cache"},
+		},
+		{
+			name:   "merged",
+			filter: WorktreeFilter{Merged: true},
+			want:   []string{"review-101", "spike-cache"},
+		},
+		{
+			name:   "older than",
+			filter: WorktreeFilter{OlderThan: 14 * 24 * time.Hour},
+			want:   []string{"review-101", "review-102"},
+		},
+		{
+			name:   "clean only",
+			filter: WorktreeFilter{CleanOnly: true},
+			want:   []string{"review-101", "spike-cache"},
+		},
+		{
+			name:   "filters combine with patterns",
+			filter: WorktreeFilter{Patterns: []string{"review-*"}, CleanOnly: true, OlderThan: 14 * 24 * time.Hour},
+			want:   []string{"review-101"},
+		},
+		{
+			name:   "nothing selected",
+			filter: WorktreeFilter{Branch: "release/*"},
+			want:   nil,
+		},
+		{
+			name:    "unmatched glob",
+			filter:  WorktreeFilter{Patterns: []string{"hotfix-*"}},
+			wantErr: "matches no worktree",
+		},
+		{
+			name:    "invalid glob",
+			filter:  WorktreeFilter{Patterns: []string{"review-["}},
+			wantErr: "invalid pattern",
+		},
+		{
+			name:    "no selection at all",
+			filter:  WorktreeFilter{},
+			wantErr: "at least one",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &MockCommandRunner{outputs: outputs}
+			manager := NewWorktreeManager(NewGitService(runner), runner)
+			tt.filter.now = func() time.Time { return now }
+
+			got, err := manager.SelectWorktrees("/repo", tt.filter)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("SelectWorktrees() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectWorktrees() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectWorktrees() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "14d", want: 14 * 24 * time.Hour},
+		{input: "2w", want: 14 * 24 * time.Hour},
+		{input: "36h", want: 36 * time.Hour},
+		{input: "1h30m", want: 90 * time.Minute},
+		{input: "d", wantErr: true},
+		{input: "-3d", wantErr: true},
+		{input: "0d", wantErr: true},
+		{input: "0s", wantErr: true},
+		{input: "-1h", wantErr: true},
+		{input: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseAge(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAge(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAge(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
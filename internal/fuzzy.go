@@ -0,0 +1,184 @@
+package internal
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Scores of fuzzy matches. A match earns scoreChar for every matched
+// character, with bonuses for characters that start a word or follow the
+// previous match, and loses scoreGap for every character skipped between
+// two matched ones.
+const (
+	scoreChar        = 1
+	scoreBoundary    = 8
+	scoreConsecutive = 4
+	scorePrefix      = 20
+	scoreGap         = 1
+	// maxGapPenalty caps the penalty of a single gap, so skipping a long
+	// word costs no more than skipping a short one.
+	maxGapPenalty = 3
+)
+
+// AmbiguityMargin is how far the best fuzzy match must score above the
+// next one to be picked without asking.
+const AmbiguityMargin = 10
+
+// WorktreeMatch is a worktree matching a fuzzy query.
+type WorktreeMatch struct {
+	Worktree Worktree
+	Score    int
+}
+
+// FuzzyScore scores how well query matches candidate, ignoring case. The
+// characters of query must appear in candidate in order; matches at word
+// starts, runs of consecutive characters and prefixes score higher.
+func FuzzyScore(query, candidate string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	c := []rune(candidate)
+	lower := []rune(strings.ToLower(candidate))
+	if len(q) == 0 || len(q) > len(c) {
+		return 0, false
+	}
+
+	// best[j] is the best score of the query so far with its last
+	// character matched at candidate position j, and run[j] the bonus of
+	// the word start that run of consecutive matches began at
+	const none = -1 << 30
+	best := make([]int, len(c))
+	run := make([]int, len(c))
+	for j := range c {
+		best[j] = none
+		if lower[j] == q[0] {
+			best[j] = scoreChar + boundaryBonus(c, j)
+			run[j] = boundaryBonus(c, j)
+		}
+	}
+	for i := 1; i < len(q); i++ {
+		next := make([]int, len(c))
+		nextRun := make([]int, len(c))
+		for j := range c {
+			next[j] = none
+			if lower[j] != q[i] {
+				continue
+			}
+			for k := 0; k < j; k++ {
+				if best[k] == none {
+					continue
+				}
+				// A run keeps earning the bonus of the word it started at
+				score, bonus := best[k]+scoreChar, boundaryBonus(c, j)
+				if k == j-1 {
+					bonus = max(bonus, run[k], scoreConsecutive)
+				} else {
+					score -= min((j-k-1)*scoreGap, maxGapPenalty)
+				}
+				if score+bonus > next[j] {
+					next[j] = score + bonus
+					nextRun[j] = bonus
+				}
+			}
+		}
+		best, run = next, nextRun
+	}
+
+	score := slices.Max(best)
+	if score == none {
+		return 0, false
+	}
+	if strings.HasPrefix(string(lower), string(q)) {
+		score += scorePrefix
+	}
+	return score, true
+}
+
+// boundaryBonus returns the bonus for matching the character at position j
+// of c, which is earned at the start of a word.
+func boundaryBonus(c []rune, j int) int {
+	if j == 0 || strings.ContainsRune("-_/. ", c[j-1]) || (unicode.IsUpper(c[j]) && unicode.IsLower(c[j-1])) {
+		return scoreBoundary
+	}
+	return 0
+}
+
+// MatchWorktrees returns the worktrees whose name or branch fuzzy matches
+// query, best match first.
+func MatchWorktrees(worktrees []Worktree, query string) []WorktreeMatch {
+	var matches []WorktreeMatch
+	for _, wt := range worktrees {
+		score, ok := FuzzyScore(query, wt.Name())
+		if branchScore, branchOK := FuzzyScore(query, wt.Branch); branchOK && (!ok || branchScore > score) {
+			score, ok = branchScore, true
+		}
+		if ok {
+			matches = append(matches, WorktreeMatch{Worktree: wt, Score: score})
+		}
+	}
+
+	// Ties go to the shorter name, which leaves less unmatched
+	slices.SortStableFunc(matches, func(a, b WorktreeMatch) int {
+		if a.Score != b.Score {
+			return b.Score - a.Score
+		}
+		return len(a.Worktree.Name()) - len(b.Worktree.Name())
+	})
+	return matches
+}
+
+// Unambiguous reports whether the first of matches, as returned by
+// MatchWorktrees, is clearly the one meant.
+func Unambiguous(matches []WorktreeMatch) bool {
+	return len(matches) == 1 || (len(matches) > 1 && matches[0].Score-matches[1].Score >= AmbiguityMargin)
+}
+
+// SimilarNames returns the names within a small edit distance of name,
+// closest first, for suggesting corrections of typos.
+func SimilarNames(names []string, name string) []string {
+	limit := max(1, len([]rune(name))/3)
+	type scored struct {
+		name     string
+		distance int
+	}
+	var similar []scored
+	for _, candidate := range names {
+		if d := editDistance(strings.ToLower(name), strings.ToLower(candidate)); d <= limit {
+			similar = append(similar, scored{candidate, d})
+		}
+	}
+	slices.SortStableFunc(similar, func(a, b scored) int { return a.distance - b.distance })
+
+	result := make([]string, len(similar))
+	for i, s := range similar {
+		result[i] = s.name
+	}
+	return result
+}
+
+// editDistance returns the number of single character insertions,
+// deletions, substitutions and swaps of adjacent characters turning a
+// into b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	var prevPrev []int
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prevPrev[j-2]+1)
+			}
+		}
+		prevPrev, prev = prev, cur
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query     string
+		candidate string
+		wantMatch bool
+	}{
+		{"auth", "feature-auth", true},
+		{"fa", "feature-auth", true},
+		{"FEAT", "feature-auth", true},
+		{"hb123", "hotfix-bug-123", true},
+		{"tuha", "feature-auth", false},
+		{"toolong", "main", false},
+		{"", "main", false},
+	}
+
+	for _, tt := range tests {
+		if _, ok := FuzzyScore(tt.query, tt.candidate); ok != tt.wantMatch {
+			t.Errorf("FuzzyScore(%q, %q) match = %v, want %v", tt.query, tt.candidate, ok, tt.wantMatch)
+		}
+	}
+}
+
+func TestFuzzyScore_Ranking(t *testing.T) {
+	// Each query should score the first candidate above the second
+	tests := []struct {
+		query         string
+		better, worse string
+	}{
+		{"feat", "feature-auth", "fix-eat-at"},    // prefix
+		{"auth", "feature-auth", "a-u-t-h"},       // consecutive
+		{"api", "api-gateway", "rapid-install"},   // start of name
+		{"login", "fix/login", "fix/long-ignore"}, // run of characters
+	}
+
+	for _, tt := range tests {
+		better, _ := FuzzyScore(tt.query, tt.better)
+		worse, _ := FuzzyScore(tt.query, tt.worse)
+		if better <= worse {
+			t.Errorf("FuzzyScore(%q): %q = %d, want above %q = %d", tt.query, tt.better, better, tt.worse, worse)
+		}
+	}
+}
+
+func TestMatchWorktrees(t *testing.T) {
+	worktrees := []Worktree{
+		{Path: "/repo", Branch: "main"},
+		{Path: "/repo/worktrees/feature-auth", Branch: "feature/auth"},
+		{Path: "/repo/worktrees/fix-auth", Branch: "fix/auth"},
+		{Path: "/repo/worktrees/hotfix-bug-123", Branch: "hotfix/bug-123"},
+	}
+
+	tests := []struct {
+		query           string
+		wantNames       []string
+		wantUnambiguous bool
+	}{
+		{"hotfix", []string{"hotfix-bug-123"}, true},
+		{"auth", []string{"fix-auth", "feature-auth"}, false},
+		{"feat", []string{"feature-auth"}, true},
+		{"zzz", nil, false},
+	}
+
+	for _, tt := range tests {
+		matches := MatchWorktrees(worktrees, tt.query)
+		var names []string
+		for _, m := range matches {
+			names = append(names, m.Worktree.Name())
+		}
+		if !reflect.DeepEqual(names, tt.wantNames) {
+			t.Errorf("MatchWorktrees(%q) = %v, want %v", tt.query, names, tt.wantNames)
+		}
+		if got := Unambiguous(matches); got != tt.wantUnambiguous {
+			t.Errorf("Unambiguous(MatchWorktrees(%q)) = %v, want %v", tt.query, got, tt.wantUnambiguous)
+		}
+	}
+}
+
+func TestSimilarNames(t *testing.T) {
+	names := []string{"main", "feature-auth", "fix-auth"}
+
+	if got, want := SimilarNames(names, "feature-atuh"), []string{"feature-auth"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SimilarNames() = %v, want %v", got, want)
+	}
+	if got := SimilarNames(names, "release"); len(got) != 0 {
+		t.Errorf("SimilarNames() = %v, want none", got)
+	}
+}
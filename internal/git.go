@@ -33,7 +33,8 @@ func parseWorktreeBlock(block string) *Worktree {
 		return nil
 	}
 
-	var path, head, branch string
+	var path, head, branch, lockReason string
+	var bare, locked bool
 
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -49,19 +50,37 @@ func parseWorktreeBlock(block string) *Worktree {
 				branch = branchRef
 			}
 		} else if line == "detached" {
-			branch = "detached HEAD"
+			branch = DetachedBranch
+		} else if line == "bare" {
+			bare = true
+		} else if line == "locked" {
+			locked = true
+		} else if strings.HasPrefix(line, "locked ") {
+			locked = true
+			lockReason = strings.TrimPrefix(line, "locked ")
 		}
 	}
 
-	if path == "" || head == "" {
+	if path == "" {
+		return nil
+	}
+
+	// The entry of a bare repository has no HEAD line
+	if bare {
+		return &Worktree{Path: path, Bare: true}
+	}
+
+	if head == "" {
 		return nil
 	}
 
 	return &Worktree{
-		Path:   path,
-		Head:   head,
-		Branch: branch,
-		Status: StatusClean,
+		Path:       path,
+		Head:       head,
+		Branch:     branch,
+		Status:     StatusClean,
+		Locked:     locked,
+		LockReason: lockReason,
 	}
 }
 
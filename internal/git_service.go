@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"strings"
@@ -10,6 +11,13 @@ type CommandRunner interface {
 	Run(command string) (string, error)
 }
 
+// ContextCommandRunner is a CommandRunner that can also stop a command once
+// its context is done.
+type ContextCommandRunner interface {
+	CommandRunner
+	RunContext(ctx context.Context, command string) (string, error)
+}
+
 type GitService struct {
 	runner CommandRunner
 }
@@ -24,10 +32,30 @@ func (g *GitService) ListWorktrees() ([]Worktree, error) {
 		return nil, err
 	}
 
-	worktrees := ParseWorktreeList(output)
+	entries := ParseWorktreeList(output)
+	if len(entries) == 0 {
+		return entries, nil
+	}
+
+	// The first entry is always the main worktree, or the repository itself
+	// when it is bare; its layout decides which worktrees are managed.
+	layout, err := LoadLayout(g.runner, entries[0].Path)
+	if err != nil {
+		return nil, err
+	}
+
+	worktrees := make([]Worktree, 0, len(entries))
+	for i, wt := range entries {
+		if wt.Bare {
+			continue
+		}
+		wt.Root = layout.Root()
+		wt.Main = i == 0
+		worktrees = append(worktrees, wt)
+	}
 
 	for i := range worktrees {
-		statusOutput, err := g.runner.Run("git -C " + worktrees[i].Path + " status --porcelain")
+		statusOutput, err := g.runner.Run("git -C " + shellescape(worktrees[i].Path) + " status --porcelain")
 		if err != nil {
 			worktrees[i].Status = StatusStale
 		} else {
@@ -39,7 +67,7 @@ func (g *GitService) ListWorktrees() ([]Worktree, error) {
 }
 
 func (g *GitService) GetDetailedStatus(worktreePath string) ([]string, error) {
-	output, err := g.runner.Run("git -C " + worktreePath + " status --porcelain")
+	output, err := g.runner.Run("git -C " + shellescape(worktreePath) + " status --porcelain")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get status for %s: %w", worktreePath, err)
 	}
@@ -101,12 +129,20 @@ func NewExecCommandRunner() *ExecCommandRunner {
 }
 
 func (e *ExecCommandRunner) Run(command string) (string, error) {
-	parts := strings.Fields(command)
+	return e.RunContext(context.Background(), command)
+}
+
+// RunContext runs command, killing it when ctx is done.
+func (e *ExecCommandRunner) RunContext(ctx context.Context, command string) (string, error) {
+	parts, err := splitCommand(command)
+	if err != nil {
+		return "", err
+	}
 	if len(parts) == 0 {
 		return "", fmt.Errorf("empty command")
 	}
 
-	cmd := exec.Command(parts[0], parts[1:]...)
+	cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
 	output, err := cmd.Output()
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
@@ -116,3 +152,46 @@ func (e *ExecCommandRunner) Run(command string) (string, error) {
 	}
 	return string(output), nil
 }
+
+// splitCommand splits a command line into arguments, honouring the single
+// and double quotes produced by shellescape. No other shell syntax is
+// interpreted.
+func splitCommand(command string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+	)
+
+	for _, r := range command {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in command: %s", command)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
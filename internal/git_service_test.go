@@ -11,6 +11,7 @@ func TestGitService_ListWorktrees(t *testing.T) {
 		name          string
 		gitOutput     string
 		statusOutputs map[string]string
+		extraOutputs  map[string]string
 		want          []Worktree
 		wantErr       bool
 	}{
@@ -28,6 +29,8 @@ branch refs/heads/main`,
 					Head:   "abc123",
 					Branch: "main",
 					Status: StatusClean,
+					Root:   "/repo/worktrees",
+					Main:   true,
 				},
 			},
 			wantErr: false,
@@ -51,12 +54,40 @@ branch refs/heads/feature/auth`,
 					Head:   "abc123",
 					Branch: "main",
 					Status: StatusClean,
+					Root:   "/repo/worktrees",
+					Main:   true,
 				},
 				{
 					Path:   "/repo/worktrees/feature-auth",
 					Head:   "def456",
 					Branch: "feature/auth",
 					Status: StatusDirty,
+					Root:   "/repo/worktrees",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "bare repository is not listed",
+			gitOutput: `worktree /src/app.git
+bare
+
+worktree /src/app.worktrees/main
+HEAD abc123
+branch refs/heads/main`,
+			statusOutputs: map[string]string{
+				"/src/app.worktrees/main": "",
+			},
+			extraOutputs: map[string]string{
+				"git -C /src/app.git rev-parse --is-bare-repository": "true\n",
+			},
+			want: []Worktree{
+				{
+					Path:   "/src/app.worktrees/main",
+					Head:   "abc123",
+					Branch: "main",
+					Status: StatusClean,
+					Root:   "/src/app.worktrees",
 				},
 			},
 			wantErr: false,
@@ -70,6 +101,9 @@ branch refs/heads/feature/auth`,
 					"git worktree list --porcelain": tt.gitOutput,
 				},
 			}
+			for cmd, output := range tt.extraOutputs {
+				mockRunner.outputs[cmd] = output
+			}
 
 			for path, status := range tt.statusOutputs {
 				mockRunner.outputs["git -C "+path+" status --porcelain"] = status
@@ -106,3 +140,55 @@ func (m *MockCommandRunner) Run(command string) (string, error) {
 func (m *MockCommandRunner) GetCommands() []string {
 	return m.commands
 }
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "plain arguments",
+			command: "git -C /repo status --porcelain",
+			want:    []string{"git", "-C", "/repo", "status", "--porcelain"},
+		},
+		{
+			name:    "escaped path with spaces",
+			command: "git -C " + shellescape("/my repo") + " status",
+			want:    []string{"git", "-C", "/my repo", "status"},
+		},
+		{
+			name:    "escaped branch with parentheses",
+			command: "git branch " + shellescape("fix(parser)"),
+			want:    []string{"git", "branch", "fix(parser)"},
+		},
+		{
+			name:    "escaped single quote",
+			command: "git branch " + shellescape("it's"),
+			want:    []string{"git", "branch", "it's"},
+		},
+		{
+			name:    "empty quoted argument",
+			command: "git commit -m ''",
+			want:    []string{"git", "commit", "-m", ""},
+		},
+		{
+			name:    "unterminated quote",
+			command: "git branch 'oops",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitCommand(tt.command)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
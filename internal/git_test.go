@@ -49,6 +49,35 @@ branch refs/heads/test-feature`,
 				},
 			},
 		},
+		{
+			name: "locked worktrees porcelain format",
+			output: `worktree /repo/worktrees/usb
+HEAD abc123
+branch refs/heads/usb
+locked on removable disk
+
+worktree /repo/worktrees/spike
+HEAD def456
+branch refs/heads/spike
+locked`,
+			want: []Worktree{
+				{
+					Path:       "/repo/worktrees/usb",
+					Head:       "abc123",
+					Branch:     "usb",
+					Status:     StatusClean,
+					Locked:     true,
+					LockReason: "on removable disk",
+				},
+				{
+					Path:   "/repo/worktrees/spike",
+					Head:   "def456",
+					Branch: "spike",
+					Status: StatusClean,
+					Locked: true,
+				},
+			},
+		},
 		{
 			name: "detached head porcelain format",
 			output: `worktree /repo
@@ -63,6 +92,27 @@ detached`,
 				},
 			},
 		},
+		{
+			name: "bare repository entry",
+			output: `worktree /src/app.git
+bare
+
+worktree /src/app.worktrees/main
+HEAD abc123
+branch refs/heads/main`,
+			want: []Worktree{
+				{
+					Path: "/src/app.git",
+					Bare: true,
+				},
+				{
+					Path:   "/src/app.worktrees/main",
+					Head:   "abc123",
+					Branch: "main",
+					Status: StatusClean,
+				},
+			},
+		},
 		{
 			name:   "empty output",
 			output: "",
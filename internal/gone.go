@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FindGoneWorktrees returns the clean managed worktrees whose branch tracks
+// an upstream branch that no longer exists, typically because it was
+// deleted after its pull request merged. With fetch set, every remote is
+// fetched with --prune first so deleted branches are noticed. Locked and
+// detached worktrees are never selected.
+func (wm *WorktreeManager) FindGoneWorktrees(repoPath string, fetch bool) ([]CleanCandidate, error) {
+	if err := validatePath(repoPath); err != nil {
+		return nil, fmt.Errorf("invalid repository path: %w", err)
+	}
+
+	if fetch {
+		if _, err := wm.runner.Run(fmt.Sprintf("git -C %s fetch --all --prune", shellescape(repoPath))); err != nil {
+			return nil, fmt.Errorf("failed to fetch: %w", err)
+		}
+	}
+
+	refCmd := fmt.Sprintf("git -C %s for-each-ref --format=%s refs/heads",
+		shellescape(repoPath),
+		shellescape("%(refname) %(upstream:short) %(upstream:track)"))
+	output, err := wm.runner.Run(refCmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upstream branches: %w", err)
+	}
+	gone := parseGoneUpstreams(output)
+
+	worktrees, err := wm.cleanableWorktrees(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []CleanCandidate
+	for _, wt := range worktrees {
+		upstream, ok := gone[wt.Branch]
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, CleanCandidate{
+			Name:     wt.Name(),
+			Path:     wt.Path,
+			Branch:   wt.Branch,
+			Evidence: fmt.Sprintf("upstream %s is gone", upstream),
+		})
+	}
+	return candidates, nil
+}
+
+// parseGoneUpstreams maps each branch whose upstream is gone to the name of
+// that upstream, from lines of "<refname> <upstream> <track>".
+func parseGoneUpstreams(output string) map[string]string {
+	gone := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || fields[2] != "[gone]" {
+			continue
+		}
+		gone[strings.TrimPrefix(fields[0], "refs/heads/")] = fields[1]
+	}
+	return gone
+}
@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestParseGoneUpstreams(t *testing.T) {
+	output := "refs/heads/main origin/main \n" +
+		"refs/heads/feature/auth origin/feature/auth [gone]\n" +
+		"refs/heads/fix origin/fix [ahead 1, behind 2]\n" +
+		"refs/heads/local  \n"
+
+	want := map[string]string{"feature/auth": "origin/feature/auth"}
+	if got := parseGoneUpstreams(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseGoneUpstreams() = %v, want %v", got, want)
+	}
+}
+
+func TestWorktreeManager_FindGoneWorktrees(t *testing.T) {
+	refCmd := "git -C /repo for-each-ref --format='%(refname) %(upstream:short) %(upstream:track)' refs/heads"
+	newRunner := func() *MockCommandRunner {
+		return &MockCommandRunner{outputs: map[string]string{
+			"git worktree list --porcelain": "worktree /repo\nHEAD aaa\nbranch refs/heads/main\n\n" +
+				"worktree /repo/worktrees/landed\nHEAD bbb\nbranch refs/heads/landed\n\n" +
+				"worktree /repo/worktrees/dirty\nHEAD ccc\nbranch refs/heads/dirty\n\n" +
+				"worktree /repo/worktrees/active\nHEAD ddd\nbranch refs/heads/active",
+			"git -C /repo status --porcelain":                  "",
+			"git -C /repo/worktrees/landed status --porcelain": "",
+			"git -C /repo/worktrees/dirty status --porcelain":  "?? notes.txt\n",
+			"git -C /repo/worktrees/active status --porcelain": "",
+			refCmd: "refs/heads/main origin/main \n" +
+				"refs/heads/landed origin/landed [gone]\n" +
+				"refs/heads/dirty origin/dirty [gone]\n" +
+				"refs/heads/active origin/active [ahead 1]\n",
+		}}
+	}
+
+	t.Run("clean worktrees with gone upstreams", func(t *testing.T) {
+		runner := newRunner()
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		gone, err := manager.FindGoneWorktrees("/repo", false)
+		if err != nil {
+			t.Fatalf("FindGoneWorktrees() error = %v (commands: %v)", err, runner.GetCommands())
+		}
+		want := []CleanCandidate{{
+			Name:     "landed",
+			Path:     "/repo/worktrees/landed",
+			Branch:   "landed",
+			Evidence: "upstream origin/landed is gone",
+		}}
+		if !reflect.DeepEqual(gone, want) {
+			t.Errorf("FindGoneWorktrees() = %+v, want %+v", gone, want)
+		}
+		if slices.Contains(runner.GetCommands(), "git -C /repo fetch --all --prune") {
+			t.Error("fetched without fetch set")
+		}
+	})
+
+	t.Run("fetch prunes first", func(t *testing.T) {
+		runner := newRunner()
+		runner.outputs["git -C /repo fetch --all --prune"] = ""
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		if _, err := manager.FindGoneWorktrees("/repo", true); err != nil {
+			t.Fatalf("FindGoneWorktrees() error = %v", err)
+		}
+		commands := runner.GetCommands()
+		if slices.Index(commands, "git -C /repo fetch --all --prune") > slices.Index(commands, refCmd) {
+			t.Errorf("fetch must come before reading upstreams, commands: %v", commands)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// HistoryLimit is the number of worktrees the switch history remembers.
+const HistoryLimit = 50
+
+// HistoryEntry records when a worktree was last switched to or from.
+type HistoryEntry struct {
+	Path string    `json:"path"`
+	Used time.Time `json:"used"`
+}
+
+// RecentWorktree is a worktree with the time it was last used, which is
+// zero if it never was.
+type RecentWorktree struct {
+	Worktree
+	Used time.Time
+}
+
+// historyPath returns the file holding the switch history of repoPath,
+// shared by all of its worktrees and every shell.
+func (wm *WorktreeManager) historyPath(repoPath string) (string, error) {
+	commonDir, err := wm.gitCommonDir(repoPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(commonDir, "wt", "history.json"), nil
+}
+
+// History returns the worktree paths switched to or from, most recent
+// first. Worktrees removed since may still be listed.
+func (wm *WorktreeManager) History(repoPath string) ([]HistoryEntry, error) {
+	path, err := wm.historyPath(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read switch history: %w", err)
+	}
+
+	var entries []HistoryEntry
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse switch history %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// RecordSwitch puts the worktree at to on top of the history, followed by
+// the one at from, so that switching back finds it even if it was entered
+// without wt. from may be empty.
+func (wm *WorktreeManager) RecordSwitch(repoPath, from, to string) error {
+	path, err := wm.historyPath(repoPath)
+	if err != nil {
+		return err
+	}
+	entries, err := wm.History(repoPath)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var recorded []HistoryEntry
+	for _, p := range []string{to, from} {
+		if p != "" && !slices.ContainsFunc(recorded, func(e HistoryEntry) bool { return e.Path == p }) {
+			recorded = append(recorded, HistoryEntry{Path: p, Used: now})
+		}
+	}
+	for _, entry := range entries {
+		if !slices.ContainsFunc(recorded, func(e HistoryEntry) bool { return e.Path == entry.Path }) {
+			recorded = append(recorded, entry)
+		}
+	}
+	if len(recorded) > HistoryLimit {
+		recorded = recorded[:HistoryLimit]
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	content, err := json.MarshalIndent(recorded, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Other shells may record at the same time; each writes its own
+	// temporary file and the last rename wins
+	tmp, err := os.CreateTemp(filepath.Dir(path), "history-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write switch history: %w", err)
+	}
+	_, writeErr := tmp.Write(append(content, '\n'))
+	closeErr := tmp.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write switch history: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write switch history: %w", err)
+	}
+	return nil
+}
+
+// RecentWorktrees orders worktrees by history, most recently used first,
+// leaving out the one at current. With all set, the worktrees never used
+// follow in the order given.
+func RecentWorktrees(worktrees []Worktree, history []HistoryEntry, current string, all bool) []RecentWorktree {
+	var recent []RecentWorktree
+	for _, entry := range history {
+		i := slices.IndexFunc(worktrees, func(wt Worktree) bool { return filepath.Clean(wt.Path) == filepath.Clean(entry.Path) })
+		if i >= 0 && filepath.Clean(worktrees[i].Path) != filepath.Clean(current) {
+			recent = append(recent, RecentWorktree{Worktree: worktrees[i], Used: entry.Used})
+		}
+	}
+	if !all {
+		return recent
+	}
+
+	for _, wt := range worktrees {
+		used := slices.ContainsFunc(recent, func(r RecentWorktree) bool { return r.Path == wt.Path })
+		if !used && filepath.Clean(wt.Path) != filepath.Clean(current) {
+			recent = append(recent, RecentWorktree{Worktree: wt})
+		}
+	}
+	return recent
+}
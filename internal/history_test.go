@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWorktreeManager_RecordSwitch(t *testing.T) {
+	commonDir := t.TempDir()
+	runner := &MockCommandRunner{outputs: map[string]string{
+		"git -C /repo rev-parse --git-common-dir": commonDir + "\n",
+	}}
+	manager := NewWorktreeManager(NewGitService(runner), runner)
+
+	paths := func() []string {
+		t.Helper()
+		entries, err := manager.History("/repo")
+		if err != nil {
+			t.Fatalf("History() error = %v", err)
+		}
+		var paths []string
+		for _, entry := range entries {
+			paths = append(paths, entry.Path)
+		}
+		return paths
+	}
+
+	if got := paths(); got != nil {
+		t.Errorf("History() without a file = %v, want nothing", got)
+	}
+
+	steps := []struct {
+		from, to string
+		want     []string
+	}{
+		{"/repo", "/repo/worktrees/a", []string{"/repo/worktrees/a", "/repo"}},
+		{"/repo/worktrees/a", "/repo/worktrees/b", []string{"/repo/worktrees/b", "/repo/worktrees/a", "/repo"}},
+		{"/repo/worktrees/b", "/repo", []string{"/repo", "/repo/worktrees/b", "/repo/worktrees/a"}},
+		// Entered outside wt, so the history did not know c
+		{"/repo/worktrees/c", "/repo/worktrees/a", []string{"/repo/worktrees/a", "/repo/worktrees/c", "/repo", "/repo/worktrees/b"}},
+		{"", "/repo/worktrees/b", []string{"/repo/worktrees/b", "/repo/worktrees/a", "/repo/worktrees/c", "/repo"}},
+	}
+	for _, step := range steps {
+		if err := manager.RecordSwitch("/repo", step.from, step.to); err != nil {
+			t.Fatalf("RecordSwitch(%q, %q) error = %v", step.from, step.to, err)
+		}
+		if got := paths(); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("after RecordSwitch(%q, %q) history = %v, want %v", step.from, step.to, got, step.want)
+		}
+	}
+
+	if matches, _ := filepath.Glob(filepath.Join(commonDir, "wt", "*.tmp")); len(matches) != 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
+
+func TestRecentWorktrees(t *testing.T) {
+	used := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	worktrees := []Worktree{
+		{Path: "/repo", Branch: "main"},
+		{Path: "/repo/worktrees/a", Branch: "a"},
+		{Path: "/repo/worktrees/b", Branch: "b"},
+		{Path: "/repo/worktrees/c", Branch: "c"},
+	}
+	history := []HistoryEntry{
+		{Path: "/repo/worktrees/b", Used: used},
+		{Path: "/repo/worktrees/gone", Used: used},
+		{Path: "/repo", Used: used},
+		{Path: "/repo/worktrees/a", Used: used},
+	}
+
+	names := func(recent []RecentWorktree) []string {
+		var names []string
+		for _, wt := range recent {
+			names = append(names, wt.Name())
+		}
+		return names
+	}
+
+	if got, want := names(RecentWorktrees(worktrees, history, "/repo/worktrees/a", false)), []string{"b", "main"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RecentWorktrees() = %v, want %v", got, want)
+	}
+
+	all := RecentWorktrees(worktrees, history, "/repo", true)
+	if got, want := names(all), []string{"b", "a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RecentWorktrees(all) = %v, want %v", got, want)
+	}
+	if !all[2].Used.IsZero() {
+		t.Errorf("never used worktree has Used = %v", all[2].Used)
+	}
+}
@@ -0,0 +1,339 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JournalLimitConfigKey is the git config key setting how many removals the
+// journal keeps. 0 disables the journal.
+const JournalLimitConfigKey = "wt.journalLimit"
+
+// DefaultJournalLimit is the number of removals kept without configuration.
+const DefaultJournalLimit = 20
+
+// RemovalEntry records a removed worktree in the journal, with everything
+// needed to add it back.
+type RemovalEntry struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Branch is empty for a detached worktree.
+	Branch        string    `json:"branch,omitempty"`
+	Head          string    `json:"head"`
+	Locked        bool      `json:"locked,omitempty"`
+	LockReason    string    `json:"lockReason,omitempty"`
+	BackupRef     string    `json:"backupRef,omitempty"`
+	ArchivePath   string    `json:"archivePath,omitempty"`
+	BranchDeleted bool      `json:"branchDeleted,omitempty"`
+	Removed       time.Time `json:"removed"`
+}
+
+// RestoreResult describes a worktree added back by Restore.
+type RestoreResult struct {
+	RemovalEntry
+	// BranchCreated is set when the branch no longer existed and was
+	// created again at Head.
+	BranchCreated bool
+	// ChangesApplied is set once the changes in BackupRef are back.
+	ChangesApplied bool
+	// BranchTip is set when the existing branch no longer pointed at Head,
+	// to the commit the worktree was checked out at instead.
+	BranchTip string
+}
+
+// journalPath returns the file holding the removals of repoPath, shared by
+// all of its worktrees.
+func (wm *WorktreeManager) journalPath(repoPath string) (string, error) {
+	commonDir, err := wm.gitCommonDir(repoPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(commonDir, "wt", "removed.json"), nil
+}
+
+func (wm *WorktreeManager) journalLimit(repoPath string) int {
+	configCmd := fmt.Sprintf("git -C %s config --get %s", shellescape(repoPath), JournalLimitConfigKey)
+	if output, err := wm.runner.Run(configCmd); err == nil {
+		if limit, err := strconv.Atoi(strings.TrimSpace(output)); err == nil && limit >= 0 {
+			return limit
+		}
+	}
+	return DefaultJournalLimit
+}
+
+// ListRemovals returns the journal of repoPath, most recent removal first.
+func (wm *WorktreeManager) ListRemovals(repoPath string) ([]RemovalEntry, error) {
+	path, err := wm.journalPath(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	return readJournal(path)
+}
+
+func readJournal(path string) ([]RemovalEntry, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read removal journal: %w", err)
+	}
+
+	var entries []RemovalEntry
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse removal journal %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+func writeJournal(path string, entries []RemovalEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write a temporary file first so a failure never truncates the journal
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(content, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write removal journal: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write removal journal: %w", err)
+	}
+	return nil
+}
+
+// recordRemovals adds the removed worktrees of results to the journal and
+// drops the oldest entries beyond the configured limit.
+func (wm *WorktreeManager) recordRemovals(repoPath string, results []RemoveResult) error {
+	limit := wm.journalLimit(repoPath)
+	if limit == 0 {
+		return nil
+	}
+
+	removed := time.Now()
+	var entries []RemovalEntry
+	for i := len(results) - 1; i >= 0; i-- {
+		r := results[i]
+		if !r.Removed {
+			continue
+		}
+		entries = append(entries, RemovalEntry{
+			Name:          r.Name,
+			Path:          r.Path,
+			Branch:        r.Branch,
+			Head:          r.head,
+			Locked:        r.locked,
+			LockReason:    r.lockReason,
+			BackupRef:     r.BackupRef,
+			ArchivePath:   r.ArchivePath,
+			BranchDeleted: r.BranchDeleted,
+			Removed:       removed,
+		})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	path, err := wm.journalPath(repoPath)
+	if err != nil {
+		return err
+	}
+	existing, err := readJournal(path)
+	if err != nil {
+		return err
+	}
+	entries = append(entries, existing...)
+	if len(entries) <= limit {
+		return writeJournal(path, entries)
+	}
+	if err := writeJournal(path, entries[:limit]); err != nil {
+		return err
+	}
+	return wm.pruneRemovals(repoPath, entries[limit:], entries[:limit])
+}
+
+// pruneRemovals deletes the backup refs and archive bundles of the entries
+// dropped from the journal, since nothing can restore them any more. Those
+// still named by a kept entry stay.
+func (wm *WorktreeManager) pruneRemovals(repoPath string, pruned, kept []RemovalEntry) error {
+	inUse := make(map[string]bool)
+	for _, entry := range kept {
+		inUse[entry.BackupRef] = true
+		inUse[entry.ArchivePath] = true
+	}
+
+	var errs []error
+	for _, entry := range pruned {
+		if entry.BackupRef != "" && !inUse[entry.BackupRef] {
+			deleteCmd := fmt.Sprintf("git -C %s update-ref -d %s", shellescape(repoPath), shellescape(entry.BackupRef))
+			if _, err := wm.runner.Run(deleteCmd); err != nil {
+				errs = append(errs, fmt.Errorf("failed to delete %s: %w", entry.BackupRef, err))
+			}
+		}
+		if entry.ArchivePath != "" && !inUse[entry.ArchivePath] {
+			// The metadata written next to the bundle goes with it
+			metadata := strings.TrimSuffix(entry.ArchivePath, ".bundle") + ".json"
+			for _, file := range []string{entry.ArchivePath, metadata} {
+				if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+					errs = append(errs, fmt.Errorf("failed to delete archive: %w", err))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Restore adds the most recently removed worktree called name back at its
+// old path, on its branch, or at its commit if it was detached, and removes
+// it from the journal. An empty name restores the most recent removal.
+//
+// A deleted branch is created again at the recorded commit, which is fetched
+// from the archive if it is gone. A branch that still exists is checked out
+// where it is now; BranchTip tells when it moved on since the removal.
+// Backed up changes are applied, after which their backup ref is deleted,
+// and the lock is put back.
+func (wm *WorktreeManager) Restore(repoPath, name string) (*RestoreResult, error) {
+	if err := validatePath(repoPath); err != nil {
+		return nil, fmt.Errorf("invalid repository path: %w", err)
+	}
+
+	path, err := wm.journalPath(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := readJournal(path)
+	if err != nil {
+		return nil, err
+	}
+
+	index := -1
+	for i, entry := range entries {
+		if name == "" || entry.Name == name {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		if name == "" {
+			return nil, fmt.Errorf("no removed worktrees to restore")
+		}
+		return nil, fmt.Errorf("no removal of worktree %q in the journal", name)
+	}
+	entry := entries[index]
+
+	// Safety: never add a worktree on top of something else
+	if _, err := os.Lstat(entry.Path); err == nil {
+		return nil, fmt.Errorf("cannot restore worktree %q: %s already exists", entry.Name, entry.Path)
+	}
+
+	if err := wm.ensureCommit(repoPath, entry); err != nil {
+		return nil, fmt.Errorf("cannot restore worktree %q: %w", entry.Name, err)
+	}
+
+	result := &RestoreResult{RemovalEntry: entry}
+	if err := wm.addRemovedWorktree(repoPath, result); err != nil {
+		return nil, fmt.Errorf("failed to restore worktree %q: %w", entry.Name, err)
+	}
+
+	// The worktree is back, so the entry goes even if a later step fails
+	var problems []string
+	remaining := append(entries[:index:index], entries[index+1:]...)
+	if err := writeJournal(path, remaining); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if entry.BackupRef != "" {
+		backup := RemoveResult{Name: entry.Name, Path: entry.Path, BackupRef: entry.BackupRef}
+		if err := wm.restoreBackups([]RemoveResult{backup}); err != nil {
+			problems = append(problems, err.Error())
+		} else {
+			result.ChangesApplied = true
+			// The changes are back in the worktree, so the ref has served its purpose
+			backupOnly := RemovalEntry{BackupRef: entry.BackupRef}
+			if err := wm.pruneRemovals(repoPath, []RemovalEntry{backupOnly}, remaining); err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+	}
+
+	if entry.Locked {
+		lockCmd := fmt.Sprintf("git -C %s worktree lock %s", shellescape(repoPath), shellescape(entry.Path))
+		if entry.LockReason != "" {
+			lockCmd = fmt.Sprintf("git -C %s worktree lock --reason %s %s",
+				shellescape(repoPath),
+				shellescape(entry.LockReason),
+				shellescape(entry.Path))
+		}
+		if _, err := wm.runner.Run(lockCmd); err != nil {
+			problems = append(problems, fmt.Sprintf("failed to lock worktree: %v", err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return result, fmt.Errorf("worktree %q restored, but %s", entry.Name, strings.Join(problems, "; "))
+	}
+	return result, nil
+}
+
+// ensureCommit makes sure the recorded HEAD of entry exists, fetching it
+// from the archive bundle when it has been garbage collected.
+func (wm *WorktreeManager) ensureCommit(repoPath string, entry RemovalEntry) error {
+	existsCmd := fmt.Sprintf("git -C %s cat-file -e %s", shellescape(repoPath), shellescape(entry.Head+"^{commit}"))
+	if _, err := wm.runner.Run(existsCmd); err == nil {
+		return nil
+	}
+	if entry.ArchivePath == "" {
+		return fmt.Errorf("commit %s no longer exists", entry.Head)
+	}
+
+	fetchCmd := fmt.Sprintf("git -C %s fetch %s HEAD", shellescape(repoPath), shellescape(entry.ArchivePath))
+	if _, err := wm.runner.Run(fetchCmd); err != nil {
+		return fmt.Errorf("commit %s no longer exists and fetching it from %s failed: %w", entry.Head, entry.ArchivePath, err)
+	}
+	if _, err := wm.runner.Run(existsCmd); err != nil {
+		return fmt.Errorf("commit %s is missing from %s", entry.Head, entry.ArchivePath)
+	}
+	return nil
+}
+
+// addRemovedWorktree adds the worktree of result back, creating its branch
+// at the recorded commit if it no longer exists.
+func (wm *WorktreeManager) addRemovedWorktree(repoPath string, result *RestoreResult) error {
+	if result.Branch == "" {
+		return wm.restoreWorktree(repoPath, RemoveResult{Path: result.Path, head: result.Head})
+	}
+
+	verifyCmd := fmt.Sprintf("git -C %s rev-parse --verify --quiet %s",
+		shellescape(repoPath),
+		shellescape("refs/heads/"+result.Branch))
+	if output, err := wm.runner.Run(verifyCmd); err == nil {
+		// Moving the branch back could lose the commits made since, so
+		// the worktree follows it and the caller is told
+		if tip := strings.TrimSpace(output); tip != result.Head {
+			result.BranchTip = tip
+		}
+		return wm.restoreWorktree(repoPath, RemoveResult{Path: result.Path, Branch: result.Branch})
+	}
+
+	gitCmd := fmt.Sprintf("git -C %s worktree add -b %s %s %s",
+		shellescape(repoPath),
+		shellescape(result.Branch),
+		shellescape(result.Path),
+		shellescape(result.Head))
+	if _, err := wm.runner.Run(gitCmd); err != nil {
+		return fmt.Errorf("failed to add worktree back: %w", err)
+	}
+	result.BranchCreated = true
+	return nil
+}
@@ -0,0 +1,262 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWorktreeManager_RemoveWorktrees_Journal(t *testing.T) {
+	newRunner := func(commonDir string) *MockCommandRunner {
+		return &MockCommandRunner{outputs: map[string]string{
+			"git worktree list --porcelain": "worktree /repo\nHEAD abc123\nbranch refs/heads/main\n\n" +
+				"worktree /repo/worktrees/feature\nHEAD def456\nbranch refs/heads/feature\n\n" +
+				"worktree /repo/worktrees/spike\nHEAD 789abc\ndetached",
+			"git -C /repo status --porcelain":                            "",
+			"git -C /repo/worktrees/feature status --porcelain":          "",
+			"git -C /repo/worktrees/spike status --porcelain":            "",
+			"git -C /repo symbolic-ref --short HEAD":                     "main\n",
+			"git -C /repo/worktrees/feature rev-list --count main..HEAD": "0\n",
+			"git -C /repo/worktrees/spike rev-list --count main..HEAD":   "0\n",
+			"git -C /repo worktree remove /repo/worktrees/feature":       "",
+			"git -C /repo worktree remove /repo/worktrees/spike":         "",
+			"git -C /repo rev-parse --git-common-dir":                    commonDir + "\n",
+		}}
+	}
+
+	t.Run("removals are recorded most recent first", func(t *testing.T) {
+		commonDir := t.TempDir()
+		runner := newRunner(commonDir)
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		if _, err := manager.RemoveWorktrees("/repo", []string{"feature", "spike"}, RemoveOptions{}); err != nil {
+			t.Fatalf("RemoveWorktrees() error = %v", err)
+		}
+
+		entries, err := manager.ListRemovals("/repo")
+		if err != nil {
+			t.Fatalf("ListRemovals() error = %v", err)
+		}
+		if len(entries) != 2 {
+			t.Fatalf("ListRemovals() = %d entries, want 2", len(entries))
+		}
+		if entries[0].Name != "spike" || entries[0].Branch != "" || entries[0].Head != "789abc" {
+			t.Errorf("entries[0] = %+v, want detached spike at 789abc", entries[0])
+		}
+		if entries[1].Name != "feature" || entries[1].Branch != "feature" || entries[1].Path != "/repo/worktrees/feature" {
+			t.Errorf("entries[1] = %+v, want feature", entries[1])
+		}
+	})
+
+	t.Run("journal is trimmed to the limit", func(t *testing.T) {
+		commonDir := t.TempDir()
+		runner := newRunner(commonDir)
+		runner.outputs["git -C /repo config --get "+JournalLimitConfigKey] = "2\n"
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		archive := filepath.Join(commonDir, "wt", "archive", "old-2-20250101-120000")
+		if err := os.MkdirAll(filepath.Dir(archive), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range []string{archive + ".bundle", archive + ".json"} {
+			if err := os.WriteFile(file, nil, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		old := []RemovalEntry{
+			{Name: "old-1", Head: "111", BackupRef: BackupRefPrefix + "old-1"},
+			{Name: "old-2", Head: "222", BackupRef: BackupRefPrefix + "old-2", ArchivePath: archive + ".bundle"},
+		}
+		if err := writeJournal(filepath.Join(commonDir, "wt", "removed.json"), old); err != nil {
+			t.Fatal(err)
+		}
+		runner.outputs["git -C /repo update-ref -d refs/wt/backups/old-2"] = ""
+		if _, err := manager.RemoveWorktrees("/repo", []string{"feature"}, RemoveOptions{}); err != nil {
+			t.Fatalf("RemoveWorktrees() error = %v", err)
+		}
+
+		entries, err := manager.ListRemovals("/repo")
+		if err != nil {
+			t.Fatalf("ListRemovals() error = %v", err)
+		}
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name)
+		}
+		if !slices.Equal(names, []string{"feature", "old-1"}) {
+			t.Errorf("journal = %v, want [feature old-1]", names)
+		}
+
+		// The backup and archive of the dropped entry go with it
+		commands := runner.GetCommands()
+		if !slices.Contains(commands, "git -C /repo update-ref -d refs/wt/backups/old-2") {
+			t.Errorf("backup ref of old-2 was not deleted, commands: %v", commands)
+		}
+		if slices.ContainsFunc(commands, func(cmd string) bool { return strings.Contains(cmd, "backups/old-1") }) {
+			t.Errorf("backup ref of the kept old-1 was touched, commands: %v", commands)
+		}
+		for _, file := range []string{archive + ".bundle", archive + ".json"} {
+			if _, err := os.Stat(file); !os.IsNotExist(err) {
+				t.Errorf("%s was not deleted (stat error %v)", file, err)
+			}
+		}
+	})
+
+	t.Run("limit of zero disables the journal", func(t *testing.T) {
+		commonDir := t.TempDir()
+		runner := newRunner(commonDir)
+		runner.outputs["git -C /repo config --get "+JournalLimitConfigKey] = "0\n"
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		if _, err := manager.RemoveWorktrees("/repo", []string{"feature"}, RemoveOptions{}); err != nil {
+			t.Fatalf("RemoveWorktrees() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(commonDir, "wt", "removed.json")); !os.IsNotExist(err) {
+			t.Errorf("journal written with a limit of 0 (stat error %v)", err)
+		}
+	})
+}
+
+func TestWorktreeManager_Restore(t *testing.T) {
+	setup := func(t *testing.T, entries ...RemovalEntry) *MockCommandRunner {
+		commonDir := t.TempDir()
+		if err := writeJournal(filepath.Join(commonDir, "wt", "removed.json"), entries); err != nil {
+			t.Fatal(err)
+		}
+		return &MockCommandRunner{outputs: map[string]string{
+			"git -C /repo rev-parse --git-common-dir": commonDir + "\n",
+		}}
+	}
+
+	t.Run("existing branch is checked out again", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "feature")
+		runner := setup(t,
+			RemovalEntry{Name: "feature", Path: path, Branch: "feature", Head: "def456", Removed: time.Now()},
+			RemovalEntry{Name: "older", Path: "/gone", Head: "111"},
+		)
+		runner.outputs["git -C /repo cat-file -e 'def456^{commit}'"] = ""
+		runner.outputs["git -C /repo rev-parse --verify --quiet refs/heads/feature"] = "def456\n"
+		runner.outputs["git -C /repo worktree add "+path+" feature"] = ""
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		result, err := manager.Restore("/repo", "")
+		if err != nil {
+			t.Fatalf("Restore() error = %v (commands: %v)", err, runner.GetCommands())
+		}
+		if result.Name != "feature" || result.BranchCreated {
+			t.Errorf("Restore() = %+v, want feature on its existing branch", result)
+		}
+
+		entries, _ := manager.ListRemovals("/repo")
+		if len(entries) != 1 || entries[0].Name != "older" {
+			t.Errorf("journal after restore = %+v, want only older", entries)
+		}
+	})
+
+	t.Run("moved branch is checked out at its tip", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "feature")
+		runner := setup(t, RemovalEntry{Name: "feature", Path: path, Branch: "feature", Head: "def456"})
+		runner.outputs["git -C /repo cat-file -e 'def456^{commit}'"] = ""
+		runner.outputs["git -C /repo rev-parse --verify --quiet refs/heads/feature"] = "fed987\n"
+		runner.outputs["git -C /repo worktree add "+path+" feature"] = ""
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		result, err := manager.Restore("/repo", "feature")
+		if err != nil {
+			t.Fatalf("Restore() error = %v (commands: %v)", err, runner.GetCommands())
+		}
+		if result.BranchTip != "fed987" || result.BranchCreated {
+			t.Errorf("Restore() = %+v, want the existing branch at fed987", result)
+		}
+	})
+
+	t.Run("deleted branch is recreated from the archive", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "spike")
+		runner := setup(t, RemovalEntry{
+			Name: "spike", Path: path, Branch: "spike", Head: "def456",
+			ArchivePath: "/archive/spike.bundle", BranchDeleted: true,
+		})
+		fetched := false
+		runner.outputs["git -C /repo fetch /archive/spike.bundle HEAD"] = ""
+		runner.outputs["git -C /repo worktree add -b spike "+path+" def456"] = ""
+		fetching := &fetchRunner{MockCommandRunner: runner, fetched: &fetched}
+		manager := NewWorktreeManager(NewGitService(fetching), fetching)
+
+		result, err := manager.Restore("/repo", "spike")
+		if err != nil {
+			t.Fatalf("Restore() error = %v (commands: %v)", err, runner.GetCommands())
+		}
+		if !fetched || !result.BranchCreated {
+			t.Errorf("fetched = %v, BranchCreated = %v, want both", fetched, result.BranchCreated)
+		}
+	})
+
+	t.Run("backup and lock are restored", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "usb")
+		runner := setup(t, RemovalEntry{
+			Name: "usb", Path: path, Head: "def456",
+			BackupRef: "refs/wt/backups/usb", Locked: true, LockReason: "on usb disk",
+		})
+		runner.outputs["git -C /repo cat-file -e 'def456^{commit}'"] = ""
+		runner.outputs["git -C /repo worktree add --detach "+path+" def456"] = ""
+		runner.outputs["git -C "+path+" stash apply refs/wt/backups/usb"] = ""
+		runner.outputs["git -C /repo worktree lock --reason 'on usb disk' "+path] = ""
+		runner.outputs["git -C /repo update-ref -d refs/wt/backups/usb"] = ""
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		result, err := manager.Restore("/repo", "usb")
+		if err != nil {
+			t.Fatalf("Restore() error = %v (commands: %v)", err, runner.GetCommands())
+		}
+		if !result.ChangesApplied {
+			t.Error("ChangesApplied = false, want true")
+		}
+		if !slices.Contains(runner.GetCommands(), "git -C /repo update-ref -d refs/wt/backups/usb") {
+			t.Errorf("commands = %v, want the backup ref deleted", runner.GetCommands())
+		}
+	})
+
+	t.Run("occupied path is refused", func(t *testing.T) {
+		path := t.TempDir()
+		runner := setup(t, RemovalEntry{Name: "feature", Path: path, Branch: "feature", Head: "def456"})
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		_, err := manager.Restore("/repo", "feature")
+		if err == nil || !strings.Contains(err.Error(), "already exists") {
+			t.Errorf("Restore() error = %v, want already exists error", err)
+		}
+		entries, _ := manager.ListRemovals("/repo")
+		if len(entries) != 1 {
+			t.Errorf("journal = %+v, want the entry kept", entries)
+		}
+	})
+
+	t.Run("unknown name", func(t *testing.T) {
+		runner := setup(t, RemovalEntry{Name: "feature", Path: "/gone", Head: "def456"})
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		_, err := manager.Restore("/repo", "other")
+		if err == nil || !strings.Contains(err.Error(), `no removal of worktree "other"`) {
+			t.Errorf("Restore() error = %v, want not found error", err)
+		}
+	})
+}
+
+// fetchRunner reports the commit as missing until it is fetched.
+type fetchRunner struct {
+	*MockCommandRunner
+	fetched *bool
+}
+
+func (f *fetchRunner) Run(command string) (string, error) {
+	if strings.Contains(command, " fetch ") {
+		*f.fetched = true
+	}
+	if strings.Contains(command, " cat-file -e ") && *f.fetched {
+		return "", nil
+	}
+	return f.MockCommandRunner.Run(command)
+}
@@ -0,0 +1,219 @@
+package internal
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+const (
+	// DefaultPathTemplate places worktrees in the worktrees/ subdirectory
+	// of the repository.
+	DefaultPathTemplate = "worktrees/{{.Name}}"
+
+	// DefaultBarePathTemplate places the worktrees of a bare repository in
+	// a sibling directory, since the repository has no working tree.
+	DefaultBarePathTemplate = "{{.RepoParent}}/{{.RepoName}}.worktrees/{{.Name}}"
+
+	// PathTemplateConfigKey is the git config key that overrides the path
+	// template for a repository.
+	PathTemplateConfigKey = "wt.pathTemplate"
+)
+
+// rootMarker stands in for the branch while rendering a template to find
+// the managed root.
+const rootMarker = "\x00wt-root\x00"
+
+// Layout describes where the managed worktrees of a repository live.
+//
+// The path template is a text/template rendered with the fields of
+// pathTemplateData. Relative results are resolved against the repository.
+// The managed root is the directory that contains the first path component
+// depending on the branch; every worktree inside it is considered managed by wt.
+type Layout struct {
+	RepoPath string
+	Template string
+	// Bare is set for bare repositories, where RepoPath is the git directory.
+	Bare bool
+
+	tmpl *template.Template
+	root string
+}
+
+type pathTemplateData struct {
+	Repo       string // absolute repository path
+	RepoParent string // directory containing the repository
+	RepoName   string // repository directory name without a .git suffix
+	Branch     string // branch name as given
+	Name       string // worktree name, the branch with slashes replaced
+}
+
+// NewLayout parses pathTemplate for the repository at repoPath.
+func NewLayout(repoPath, pathTemplate string) (*Layout, error) {
+	return newLayout(repoPath, pathTemplate, false)
+}
+
+// NewBareLayout parses pathTemplate for the bare repository at repoPath.
+func NewBareLayout(repoPath, pathTemplate string) (*Layout, error) {
+	return newLayout(repoPath, pathTemplate, true)
+}
+
+func newLayout(repoPath, pathTemplate string, bare bool) (*Layout, error) {
+	tmpl, err := template.New("path").Option("missingkey=error").Parse(pathTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path template %q: %w", pathTemplate, err)
+	}
+
+	l := &Layout{
+		RepoPath: filepath.Clean(repoPath),
+		Template: pathTemplate,
+		Bare:     bare,
+		tmpl:     tmpl,
+	}
+
+	marked, err := l.render(rootMarker, rootMarker)
+	if err != nil {
+		return nil, err
+	}
+	idx := strings.Index(marked, rootMarker)
+	if idx < 0 {
+		return nil, fmt.Errorf("path template %q must use {{.Name}} or {{.Branch}}", pathTemplate)
+	}
+	// Names are read back from the path below the root, which only works
+	// when the branch makes up the whole rest of the path
+	if !strings.HasSuffix(marked[:idx], string(filepath.Separator)) || !strings.HasSuffix(marked, rootMarker) {
+		return nil, fmt.Errorf("path template %q must end with {{.Name}} or {{.Branch}} as a whole path component", pathTemplate)
+	}
+	l.root = filepath.Clean(marked[:idx])
+	if l.root == l.RepoPath {
+		return nil, fmt.Errorf("path template %q must place worktrees in a subdirectory, not the repository root", pathTemplate)
+	}
+	// Every directory in the root is taken for a worktree, so it must not
+	// hold the repository and whatever lies beside it. wt clone --bare is
+	// the exception: its directory holds nothing but .bare and worktrees.
+	if _, inside := relativeTo(l.root, l.RepoPath); inside && !l.isBareClone() {
+		return nil, fmt.Errorf("path template %q must place worktrees in a directory of their own, not in %s which contains the repository", pathTemplate, l.root)
+	}
+	// The git directory holds its own worktrees/ administrative files
+	if _, inside := relativeTo(l.RepoPath, l.root); inside && bare {
+		return nil, fmt.Errorf("path template %q must place worktrees outside the bare repository", pathTemplate)
+	}
+
+	return l, nil
+}
+
+// isBareClone reports whether the layout is that of wt clone --bare, with
+// the worktrees beside the .bare git directory.
+func (l *Layout) isBareClone() bool {
+	return l.Bare && filepath.Base(l.RepoPath) == bareCloneDir && l.root == filepath.Dir(l.RepoPath)
+}
+
+// LoadLayout reads the path template configured for the repository,
+// falling back to DefaultPathTemplate, or DefaultBarePathTemplate for bare
+// repositories, when none is set.
+func LoadLayout(runner CommandRunner, repoPath string) (*Layout, error) {
+	bareCmd := fmt.Sprintf("git -C %s rev-parse --is-bare-repository", shellescape(repoPath))
+	output, err := runner.Run(bareCmd)
+	bare := err == nil && strings.TrimSpace(output) == "true"
+
+	pathTemplate := DefaultPathTemplate
+	if bare {
+		pathTemplate = DefaultBarePathTemplate
+	}
+
+	configCmd := fmt.Sprintf("git -C %s config --get %s", shellescape(repoPath), PathTemplateConfigKey)
+	// git config exits non-zero when the key is unset
+	if output, err := runner.Run(configCmd); err == nil {
+		if configured := strings.TrimSpace(output); configured != "" {
+			pathTemplate = configured
+		}
+	}
+
+	newLayout := NewLayout
+	if bare {
+		newLayout = NewBareLayout
+	}
+	layout, err := newLayout(repoPath, pathTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", PathTemplateConfigKey, err)
+	}
+	return layout, nil
+}
+
+// Root returns the directory that holds all managed worktrees.
+func (l *Layout) Root() string {
+	return l.root
+}
+
+// WorktreePath returns the path of the worktree for branch.
+func (l *Layout) WorktreePath(branch string) (string, error) {
+	path, err := l.render(branch, BranchToWorktreeName(branch))
+	if err != nil {
+		return "", err
+	}
+	if !l.IsManaged(path) {
+		return "", fmt.Errorf("path %q for branch %q is outside the managed directory %s", path, branch, l.root)
+	}
+	return path, nil
+}
+
+// IsManaged reports whether path lies inside the managed root.
+func (l *Layout) IsManaged(path string) bool {
+	_, ok := relativeTo(l.root, path)
+	return ok
+}
+
+// Matches reports whether path is where the template places a worktree:
+// inside the managed root, at the path rendered for the branch it names.
+func (l *Layout) Matches(path string) bool {
+	rel, ok := relativeTo(l.root, path)
+	if !ok {
+		return false
+	}
+	branch := filepath.ToSlash(rel)
+	rendered, err := l.render(branch, BranchToWorktreeName(branch))
+	return err == nil && rendered == filepath.Clean(path)
+}
+
+// GitignoreEntry returns the .gitignore pattern that hides the managed
+// root, or false when the root is outside the working tree.
+func (l *Layout) GitignoreEntry() (string, bool) {
+	if l.Bare {
+		return "", false
+	}
+	rel, ok := relativeTo(l.RepoPath, l.root)
+	if !ok {
+		return "", false
+	}
+	return filepath.ToSlash(rel) + "/", true
+}
+
+func (l *Layout) render(branch, name string) (string, error) {
+	var b strings.Builder
+	data := pathTemplateData{
+		Repo:       l.RepoPath,
+		RepoParent: filepath.Dir(l.RepoPath),
+		RepoName:   strings.TrimSuffix(filepath.Base(l.RepoPath), ".git"),
+		Branch:     branch,
+		Name:       name,
+	}
+	if err := l.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render path template %q: %w", l.Template, err)
+	}
+
+	path := b.String()
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(l.RepoPath, path)
+	}
+	return filepath.Clean(path), nil
+}
+
+// relativeTo returns path relative to dir if path lies strictly inside dir.
+func relativeTo(dir, path string) (string, bool) {
+	rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(path))
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
@@ -0,0 +1,270 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLayout_WorktreePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoPath string
+		template string
+		branch   string
+		wantPath string
+		wantRoot string
+	}{
+		{
+			name:     "default template",
+			repoPath: "/src/app",
+			template: DefaultPathTemplate,
+			branch:   "feature/auth",
+			wantPath: "/src/app/worktrees/feature-auth",
+			wantRoot: "/src/app/worktrees",
+		},
+		{
+			name:     "sibling directory",
+			repoPath: "/src/app",
+			template: "{{.RepoParent}}/{{.RepoName}}.worktrees/{{.Name}}",
+			branch:   "feature/auth",
+			wantPath: "/src/app.worktrees/feature-auth",
+			wantRoot: "/src/app.worktrees",
+		},
+		{
+			name:     "nested branch directories",
+			repoPath: "/src/app",
+			template: ".worktrees/{{.Branch}}",
+			branch:   "feature/auth",
+			wantPath: "/src/app/.worktrees/feature/auth",
+			wantRoot: "/src/app/.worktrees",
+		},
+		{
+			name:     "repository name drops .git suffix",
+			repoPath: "/src/app.git",
+			template: "{{.RepoParent}}/{{.RepoName}}/{{.Name}}",
+			branch:   "main",
+			wantPath: "/src/app/main",
+			wantRoot: "/src/app",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := NewLayout(tt.repoPath, tt.template)
+			if err != nil {
+				t.Fatalf("NewLayout() error = %v", err)
+			}
+
+			got, err := layout.WorktreePath(tt.branch)
+			if err != nil {
+				t.Fatalf("WorktreePath() error = %v", err)
+			}
+			if got != tt.wantPath {
+				t.Errorf("WorktreePath() = %q, want %q", got, tt.wantPath)
+			}
+			if layout.Root() != tt.wantRoot {
+				t.Errorf("Root() = %q, want %q", layout.Root(), tt.wantRoot)
+			}
+			if !layout.IsManaged(got) {
+				t.Errorf("IsManaged(%q) = false, want true", got)
+			}
+		})
+	}
+}
+
+func TestNewLayout_InvalidTemplates(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		errMsg   string
+	}{
+		{name: "no branch placeholder", template: "worktrees/fixed", errMsg: "must use"},
+		{name: "repository root", template: "{{.Name}}", errMsg: "subdirectory"},
+		{name: "prefix before the name", template: "{{.RepoParent}}/{{.RepoName}}-{{.Name}}", errMsg: "whole path component"},
+		{name: "suffix after the branch", template: "worktrees/{{.Branch}}.wt", errMsg: "whole path component"},
+		{name: "directory below the name", template: "worktrees/{{.Name}}/src", errMsg: "must end with"},
+		{name: "root containing the repository", template: "{{.RepoParent}}/{{.Name}}", errMsg: "contains the repository"},
+		{name: "unknown field", template: "worktrees/{{.Nope}}", errMsg: "failed to render"},
+		{name: "syntax error", template: "worktrees/{{.Name", errMsg: "invalid path template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLayout("/repo", tt.template)
+			if err == nil {
+				t.Fatal("NewLayout() expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("NewLayout() error = %v, want error containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestLayout_IsManaged(t *testing.T) {
+	layout, err := NewLayout("/repo", DefaultPathTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "/repo/worktrees/feature", want: true},
+		{path: "/repo/worktrees/feature/nested", want: true},
+		{path: "/repo/worktrees", want: false},
+		{path: "/repo", want: false},
+		{path: "/repo/src/worktrees/feature", want: false},
+		{path: "/repo/worktrees-old/feature", want: false},
+		{path: "/elsewhere/worktrees/feature", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := layout.IsManaged(tt.path); got != tt.want {
+				t.Errorf("IsManaged(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayout_Matches(t *testing.T) {
+	tests := []struct {
+		template string
+		path     string
+		want     bool
+	}{
+		{template: DefaultPathTemplate, path: "/repo/worktrees/feature-auth", want: true},
+		{template: DefaultPathTemplate, path: "/repo/worktrees/feature/auth", want: false},
+		{template: DefaultPathTemplate, path: "/repo/src", want: false},
+		{template: ".worktrees/{{.Branch}}", path: "/repo/.worktrees/feature/auth", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template+" "+tt.path, func(t *testing.T) {
+			layout, err := NewLayout("/repo", tt.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := layout.Matches(tt.path); got != tt.want {
+				t.Errorf("Matches(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayout_GitignoreEntry(t *testing.T) {
+	tests := []struct {
+		name      string
+		template  string
+		wantEntry string
+		wantOK    bool
+	}{
+		{name: "default", template: DefaultPathTemplate, wantEntry: "worktrees/", wantOK: true},
+		{name: "hidden nested", template: ".wt/trees/{{.Branch}}", wantEntry: ".wt/trees/", wantOK: true},
+		{name: "outside repository", template: "{{.RepoParent}}/trees/{{.Name}}", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := NewLayout("/repo", tt.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			entry, ok := layout.GitignoreEntry()
+			if ok != tt.wantOK || entry != tt.wantEntry {
+				t.Errorf("GitignoreEntry() = %q, %v, want %q, %v", entry, ok, tt.wantEntry, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLoadLayout(t *testing.T) {
+	configCmd := "git -C /repo config --get " + PathTemplateConfigKey
+
+	t.Run("unset uses default", func(t *testing.T) {
+		layout, err := LoadLayout(&MockCommandRunner{outputs: map[string]string{}}, "/repo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if layout.Template != DefaultPathTemplate {
+			t.Errorf("Template = %q, want %q", layout.Template, DefaultPathTemplate)
+		}
+	})
+
+	t.Run("configured template", func(t *testing.T) {
+		runner := &MockCommandRunner{outputs: map[string]string{
+			configCmd: "{{.RepoParent}}/{{.RepoName}}.worktrees/{{.Name}}\n",
+		}}
+		layout, err := LoadLayout(runner, "/repo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if layout.Root() != "/repo.worktrees" {
+			t.Errorf("Root() = %q, want %q", layout.Root(), "/repo.worktrees")
+		}
+	})
+
+	t.Run("invalid template is reported", func(t *testing.T) {
+		runner := &MockCommandRunner{outputs: map[string]string{configCmd: "fixed\n"}}
+		_, err := LoadLayout(runner, "/repo")
+		if err == nil || !strings.Contains(err.Error(), PathTemplateConfigKey) {
+			t.Errorf("LoadLayout() error = %v, want error mentioning %s", err, PathTemplateConfigKey)
+		}
+	})
+}
+
+func TestLoadLayout_BareRepository(t *testing.T) {
+	bareCmd := "git -C /src/app.git rev-parse --is-bare-repository"
+	configCmd := "git -C /src/app.git config --get " + PathTemplateConfigKey
+
+	t.Run("default places worktrees beside the repository", func(t *testing.T) {
+		runner := &MockCommandRunner{outputs: map[string]string{bareCmd: "true\n"}}
+		layout, err := LoadLayout(runner, "/src/app.git")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !layout.Bare {
+			t.Error("Bare = false, want true")
+		}
+		path, err := layout.WorktreePath("main")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path != "/src/app.worktrees/main" {
+			t.Errorf("WorktreePath() = %q, want %q", path, "/src/app.worktrees/main")
+		}
+		if _, ok := layout.GitignoreEntry(); ok {
+			t.Error("GitignoreEntry() should be skipped for bare repositories")
+		}
+	})
+
+	t.Run("worktrees inside the git directory are rejected", func(t *testing.T) {
+		runner := &MockCommandRunner{outputs: map[string]string{
+			bareCmd:   "true\n",
+			configCmd: DefaultPathTemplate,
+		}}
+		_, err := LoadLayout(runner, "/src/app.git")
+		if err == nil || !strings.Contains(err.Error(), "outside the bare repository") {
+			t.Errorf("LoadLayout() error = %v, want error about bare repository", err)
+		}
+	})
+
+	t.Run("bare clone keeps worktrees beside .bare", func(t *testing.T) {
+		layout, err := NewBareLayout("/src/app/.bare", "{{.RepoParent}}/{{.Name}}")
+		if err != nil {
+			t.Fatalf("NewBareLayout() error = %v", err)
+		}
+		if layout.Root() != "/src/app" {
+			t.Errorf("Root() = %q, want %q", layout.Root(), "/src/app")
+		}
+	})
+
+	t.Run("other bare repositories may not share their parent", func(t *testing.T) {
+		_, err := NewBareLayout("/src/app.git", "{{.RepoParent}}/{{.Name}}")
+		if err == nil || !strings.Contains(err.Error(), "contains the repository") {
+			t.Errorf("NewBareLayout() error = %v, want error about the root", err)
+		}
+	})
+}
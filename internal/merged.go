@@ -0,0 +1,208 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CleanCandidate is a worktree that wt clean offers to remove.
+type CleanCandidate struct {
+	Name   string
+	Path   string
+	Branch string
+	// Evidence explains why the worktree is no longer needed.
+	Evidence string
+}
+
+// FindMergedWorktrees returns the clean managed worktrees whose branches are
+// merged into base, in the order git lists them. An empty base means the
+// configured base branch.
+//
+// Besides regular merges, branches whose commits were rebased or squashed
+// onto base are detected by comparing patch-ids and trees. Locked and
+// detached worktrees are never selected, and neither are branches at the
+// tip of base, which have no commits of their own yet.
+func (wm *WorktreeManager) FindMergedWorktrees(repoPath, base string) ([]CleanCandidate, error) {
+	if err := validatePath(repoPath); err != nil {
+		return nil, fmt.Errorf("invalid repository path: %w", err)
+	}
+
+	if base == "" {
+		var err error
+		if base, err = wm.baseBranch(repoPath); err != nil {
+			return nil, err
+		}
+	}
+	verifyCmd := fmt.Sprintf("git -C %s rev-parse --verify --quiet %s",
+		shellescape(repoPath),
+		shellescape("refs/heads/"+base))
+	if _, err := wm.runner.Run(verifyCmd); err != nil {
+		return nil, fmt.Errorf("base branch %q does not exist", base)
+	}
+
+	worktrees, err := wm.cleanableWorktrees(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var merged []CleanCandidate
+	for _, wt := range worktrees {
+		if wt.Branch == base {
+			continue
+		}
+		evidence, err := wm.mergeEvidence(repoPath, wt.Head, base)
+		if err != nil {
+			return nil, fmt.Errorf("cannot check whether %s is merged: %w", wt.Name(), err)
+		}
+		if evidence == "" {
+			continue
+		}
+		merged = append(merged, CleanCandidate{
+			Name:     wt.Name(),
+			Path:     wt.Path,
+			Branch:   wt.Branch,
+			Evidence: evidence,
+		})
+	}
+	return merged, nil
+}
+
+// cleanableWorktrees returns the managed worktrees wt clean may remove:
+// those with a branch checked out, no uncommitted changes and no lock.
+func (wm *WorktreeManager) cleanableWorktrees(repoPath string) ([]Worktree, error) {
+	layout, err := LoadLayout(wm.runner, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	worktrees, err := wm.gitService.ListWorktrees()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	var cleanable []Worktree
+	for _, wt := range worktrees {
+		if wt.Bare || wt.Path == layout.RepoPath || !layout.IsManaged(wt.Path) {
+			continue
+		}
+		if wt.Status != StatusClean || wt.Locked || wt.Detached() {
+			continue
+		}
+		cleanable = append(cleanable, wt)
+	}
+	return cleanable, nil
+}
+
+// mergeEvidence describes how head has been merged into base, or returns
+// an empty string if it has not.
+func (wm *WorktreeManager) mergeEvidence(repoPath, head, base string) (string, error) {
+	baseRef := "refs/heads/" + base
+
+	// A regular merge or fast-forward
+	ancestorCmd := fmt.Sprintf("git -C %s merge-base --is-ancestor %s %s",
+		shellescape(repoPath),
+		shellescape(head),
+		shellescape(baseRef))
+	if _, err := wm.runner.Run(ancestorCmd); err == nil {
+		if atTip, err := wm.atBaseTip(repoPath, head, base); err != nil || atTip {
+			return "", err
+		}
+		return fmt.Sprintf("merged into %s", base), nil
+	}
+
+	output, err := wm.runner.Run(fmt.Sprintf("git -C %s merge-base %s %s",
+		shellescape(repoPath),
+		shellescape(baseRef),
+		shellescape(head)))
+	forkPoint := strings.TrimSpace(output)
+	if err != nil || forkPoint == "" {
+		// Unrelated histories cannot have been merged
+		return "", nil
+	}
+
+	// Every commit rebased or cherry-picked onto base
+	applied, total, err := wm.cherry(repoPath, baseRef, head, forkPoint)
+	if err != nil {
+		return "", err
+	}
+	if total > 0 && applied == total {
+		return fmt.Sprintf("all %d commit(s) applied to %s (same patch-ids)", total, base), nil
+	}
+
+	// A commit on base with exactly the branch's content
+	tree, err := wm.runner.Run(fmt.Sprintf("git -C %s rev-parse %s", shellescape(repoPath), shellescape(head+"^{tree}")))
+	if err != nil {
+		return "", fmt.Errorf("failed to read tree of %s: %w", head, err)
+	}
+	logCmd := fmt.Sprintf("git -C %s log --format=%s %s",
+		shellescape(repoPath),
+		shellescape("%H %T"),
+		shellescape(forkPoint+".."+baseRef))
+	output, err = wm.runner.Run(logCmd)
+	if err != nil {
+		return "", fmt.Errorf("failed to read history of %s: %w", base, err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		commit, commitTree, ok := strings.Cut(line, " ")
+		if ok && commitTree == strings.TrimSpace(tree) {
+			return fmt.Sprintf("tree matches %s commit %s", base, ShortCommit(commit)), nil
+		}
+	}
+
+	// A squash merge: the whole branch as one commit has a counterpart on
+	// base. The temporary commit is unreferenced and left to git gc.
+	squashCmd := fmt.Sprintf("git -C %s -c user.name=wt -c user.email=wt@localhost commit-tree %s -p %s -m %s",
+		shellescape(repoPath),
+		shellescape(head+"^{tree}"),
+		shellescape(forkPoint),
+		shellescape("wt squash check"))
+	output, err = wm.runner.Run(squashCmd)
+	squashed := strings.TrimSpace(output)
+	if err != nil || squashed == "" {
+		return "", fmt.Errorf("failed to build squashed commit: %w", err)
+	}
+	applied, total, err = wm.cherry(repoPath, baseRef, squashed, forkPoint)
+	if err != nil {
+		return "", err
+	}
+	if total == 1 && applied == 1 {
+		return fmt.Sprintf("squash-merged into %s (same patch-id)", base), nil
+	}
+
+	return "", nil
+}
+
+// atBaseTip reports whether head is the tip of base. Such a branch has no
+// commits of its own, which is what a branch just created for new work looks
+// like, so it does not count as merged.
+func (wm *WorktreeManager) atBaseTip(repoPath, head, base string) (bool, error) {
+	tip, err := wm.runner.Run(fmt.Sprintf("git -C %s rev-parse %s", shellescape(repoPath), shellescape("refs/heads/"+base)))
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve %s: %w", base, err)
+	}
+	return strings.TrimSpace(tip) == head, nil
+}
+
+// cherry counts the commits in limit..head and how many of them have an
+// equivalent change in upstream, according to git cherry.
+func (wm *WorktreeManager) cherry(repoPath, upstream, head, limit string) (applied, total int, err error) {
+	cherryCmd := fmt.Sprintf("git -C %s cherry %s %s %s",
+		shellescape(repoPath),
+		shellescape(upstream),
+		shellescape(head),
+		shellescape(limit))
+	output, err := wm.runner.Run(cherryCmd)
+	if err != nil {
+		return 0, 0, fmt.Errorf("git cherry failed: %w", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		switch {
+		case strings.HasPrefix(line, "- "):
+			applied++
+			total++
+		case strings.HasPrefix(line, "+ "):
+			total++
+		}
+	}
+	return applied, total, nil
+}
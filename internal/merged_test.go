@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWorktreeManager_FindMergedWorktrees(t *testing.T) {
+	listOutput := "worktree /repo\nHEAD aaa\nbranch refs/heads/main\n\n" +
+		"worktree /repo/worktrees/merged\nHEAD bbb\nbranch refs/heads/merged\n\n" +
+		"worktree /repo/worktrees/rebased\nHEAD ccc\nbranch refs/heads/rebased\n\n" +
+		"worktree /repo/worktrees/squashed\nHEAD ddd\nbranch refs/heads/squashed\n\n" +
+		"worktree /repo/worktrees/open\nHEAD eee\nbranch refs/heads/open\n\n" +
+		"worktree /repo/worktrees/fresh\nHEAD aaa\nbranch refs/heads/fresh\n\n" +
+		"worktree /repo/worktrees/dirty\nHEAD bbb\nbranch refs/heads/dirty\n\n" +
+		"worktree /repo/worktrees/locked\nHEAD bbb\nbranch refs/heads/locked\nlocked\n\n" +
+		"worktree /repo/worktrees/detached\nHEAD bbb\ndetached"
+
+	newRunner := func() *MockCommandRunner {
+		outputs := map[string]string{
+			"git worktree list --porcelain":                           listOutput,
+			"git -C /repo symbolic-ref --short HEAD":                  "main\n",
+			"git -C /repo rev-parse --verify --quiet refs/heads/main": "aaa\n",
+			"git -C /repo rev-parse refs/heads/main":                  "aaa\n",
+			"git -C /repo/worktrees/dirty status --porcelain":         " M file.go\n",
+
+			"git -C /repo merge-base --is-ancestor bbb refs/heads/main": "",
+			// fresh: created from main, no commits of its own
+			"git -C /repo merge-base --is-ancestor aaa refs/heads/main": "",
+
+			// rebased: both commits have an equivalent on main
+			"git -C /repo merge-base refs/heads/main ccc":  "fork\n",
+			"git -C /repo cherry refs/heads/main ccc fork": "- c1\n- c2\n",
+
+			// squashed: only the combined diff matches
+			"git -C /repo merge-base refs/heads/main ddd":                                                                   "fork\n",
+			"git -C /repo cherry refs/heads/main ddd fork":                                                                  "+ d1\n+ d2\n",
+			"git -C /repo rev-parse 'ddd^{tree}'":                                                                           "tree-d\n",
+			"git -C /repo log --format='%H %T' fork..refs/heads/main":                                                       "m1 tree-m\n",
+			"git -C /repo -c user.name=wt -c user.email=wt@localhost commit-tree 'ddd^{tree}' -p fork -m 'wt squash check'": "tmp-d\n",
+			"git -C /repo cherry refs/heads/main tmp-d fork":                                                                "- tmp-d\n",
+
+			// open: nothing matches
+			"git -C /repo merge-base refs/heads/main eee":  "fork\n",
+			"git -C /repo cherry refs/heads/main eee fork": "+ e1\n",
+			"git -C /repo rev-parse 'eee^{tree}'":          "tree-e\n",
+			"git -C /repo -c user.name=wt -c user.email=wt@localhost commit-tree 'eee^{tree}' -p fork -m 'wt squash check'": "tmp-e\n",
+			"git -C /repo cherry refs/heads/main tmp-e fork":                                                                "+ tmp-e\n",
+		}
+		for _, name := range []string{"", "/worktrees/merged", "/worktrees/rebased", "/worktrees/squashed", "/worktrees/open", "/worktrees/fresh", "/worktrees/locked", "/worktrees/detached"} {
+			outputs["git -C /repo"+name+" status --porcelain"] = ""
+		}
+		return &MockCommandRunner{outputs: outputs}
+	}
+
+	t.Run("merged, rebased and squashed branches are found", func(t *testing.T) {
+		runner := newRunner()
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		merged, err := manager.FindMergedWorktrees("/repo", "")
+		if err != nil {
+			t.Fatalf("FindMergedWorktrees() error = %v (commands: %v)", err, runner.GetCommands())
+		}
+		want := []CleanCandidate{
+			{Name: "merged", Path: "/repo/worktrees/merged", Branch: "merged", Evidence: "merged into main"},
+			{Name: "rebased", Path: "/repo/worktrees/rebased", Branch: "rebased", Evidence: "all 2 commit(s) applied to main (same patch-ids)"},
+			{Name: "squashed", Path: "/repo/worktrees/squashed", Branch: "squashed", Evidence: "squash-merged into main (same patch-id)"},
+		}
+		if !reflect.DeepEqual(merged, want) {
+			t.Errorf("FindMergedWorktrees() = %+v, want %+v", merged, want)
+		}
+	})
+
+	t.Run("matching tree counts as merged", func(t *testing.T) {
+		runner := newRunner()
+		runner.outputs["git -C /repo log --format='%H %T' fork..refs/heads/main"] = "m1 tree-m\nm2 tree-e\n"
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		merged, err := manager.FindMergedWorktrees("/repo", "")
+		if err != nil {
+			t.Fatalf("FindMergedWorktrees() error = %v", err)
+		}
+		last := merged[len(merged)-1]
+		if last.Name != "open" || last.Evidence != "tree matches main commit m2" {
+			t.Errorf("last merged worktree = %+v, want open with matching tree", last)
+		}
+	})
+
+	t.Run("missing base branch", func(t *testing.T) {
+		runner := newRunner()
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		_, err := manager.FindMergedWorktrees("/repo", "develop")
+		if err == nil || !strings.Contains(err.Error(), `base branch "develop" does not exist`) {
+			t.Errorf("FindMergedWorktrees() error = %v, want missing base error", err)
+		}
+	})
+}
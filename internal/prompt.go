@@ -0,0 +1,352 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPromptFormat shows the worktree name, its branch when that differs
+// and the status markers.
+const DefaultPromptFormat = "{{.Name}}{{if ne .Name .Branch}} ({{.Branch}}){{end}}{{with .Markers}} {{.}}{{end}}"
+
+// DefaultPromptTimeout is how long the prompt waits for git status.
+const DefaultPromptTimeout = 150 * time.Millisecond
+
+// PromptInfo is what a shell prompt shows about the current worktree.
+type PromptInfo struct {
+	Name   string
+	Branch string
+	Path   string
+	// Main is set in the repository's primary worktree.
+	Main bool
+	// HasStatus is set when the fields below were read in time.
+	HasStatus bool
+	Dirty     bool
+	// Ahead and Behind count the commits that differ from the upstream.
+	Ahead  int
+	Behind int
+}
+
+// Markers returns * for uncommitted changes, ↑N for commits ahead of the
+// upstream and ↓N for commits behind it.
+func (p PromptInfo) Markers() string {
+	var markers strings.Builder
+	if p.Dirty {
+		markers.WriteString("*")
+	}
+	if p.Ahead > 0 {
+		fmt.Fprintf(&markers, "↑%d", p.Ahead)
+	}
+	if p.Behind > 0 {
+		fmt.Fprintf(&markers, "↓%d", p.Behind)
+	}
+	return markers.String()
+}
+
+// PromptOptions controls how much work Prompt may do.
+type PromptOptions struct {
+	// Status reads the dirty and ahead/behind state with git status.
+	Status bool
+	// Timeout bounds git status; past it the status is left out.
+	Timeout time.Duration
+	// CacheTTL reuses a status read this recently, unless HEAD or the
+	// index changed since. Zero disables the cache.
+	CacheTTL time.Duration
+}
+
+// promptStatus is the part of PromptInfo read from git status.
+type promptStatus struct {
+	Dirty  bool `json:"dirty,omitempty"`
+	Ahead  int  `json:"ahead,omitempty"`
+	Behind int  `json:"behind,omitempty"`
+}
+
+// promptCacheEntry is a cached status with what it was read against.
+type promptCacheEntry struct {
+	promptStatus
+	Head    string    `json:"head"`
+	Index   time.Time `json:"index"`
+	Checked time.Time `json:"checked"`
+}
+
+// Prompt returns the prompt information for the worktree containing dir, or
+// nil outside of one. Only files are read to find the worktree, its branch
+// and name, so no other worktree is looked at; with opts.Status a single
+// git status runs, within opts.Timeout.
+//
+// The name is derived as wt list does, from the path below the managed root
+// of wt.pathTemplate, which is read from the repository's config file.
+func (wm *WorktreeManager) Prompt(dir string, opts PromptOptions) (*PromptInfo, error) {
+	path, gitDir, ok := findGitDir(dir)
+	if !ok {
+		return nil, nil
+	}
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HEAD: %w", err)
+	}
+	commonDir := gitDir
+	if content, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir = strings.TrimSpace(string(content))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+	}
+
+	config, _ := os.ReadFile(filepath.Join(commonDir, "config"))
+	bare := gitConfigValue(string(config), "core", "bare") == "true"
+	main := filepath.Clean(commonDir) == filepath.Clean(gitDir)
+	// The directory of a bare clone points at the repository, which has
+	// no working tree
+	if main && bare {
+		return nil, nil
+	}
+
+	wt := Worktree{Path: path, Head: strings.TrimSpace(string(head))}
+	if branch, ok := strings.CutPrefix(wt.Head, "ref: refs/heads/"); ok {
+		wt.Branch = branch
+	} else {
+		wt.Branch = DetachedBranch
+	}
+	if layout, err := promptLayout(commonDir, string(config), bare); err == nil {
+		wt.Root = layout.Root()
+	}
+	info := &PromptInfo{
+		Name:   wt.Name(),
+		Branch: wt.Branch,
+		Path:   path,
+		Main:   main,
+	}
+	if wt.Detached() {
+		info.Branch = ShortCommit(wt.Head)
+		if _, managed := relativeTo(wt.Root, path); !managed {
+			info.Name = filepath.Base(path)
+		}
+	}
+	if !opts.Status {
+		return info, nil
+	}
+
+	var index time.Time
+	if stat, err := os.Stat(filepath.Join(gitDir, "index")); err == nil {
+		index = stat.ModTime()
+	}
+	cachePath := filepath.Join(commonDir, "wt", "prompt.json")
+	var cache map[string]promptCacheEntry
+	if opts.CacheTTL > 0 {
+		cache = readPromptCache(cachePath)
+		if entry, ok := cache[path]; ok && entry.Head == wt.Head && entry.Index.Equal(index) && time.Since(entry.Checked) < opts.CacheTTL {
+			info.setStatus(entry.promptStatus)
+			return info, nil
+		}
+	}
+
+	status, ok := wm.promptStatus(path, opts.Timeout)
+	if !ok {
+		return info, nil
+	}
+	info.setStatus(status)
+	if opts.CacheTTL > 0 {
+		if cache == nil {
+			cache = make(map[string]promptCacheEntry)
+		}
+		cache[path] = promptCacheEntry{promptStatus: status, Head: wt.Head, Index: index, Checked: time.Now()}
+		// The prompt must not fail for the cache
+		_ = writePromptCache(cachePath, cache)
+	}
+	return info, nil
+}
+
+func (p *PromptInfo) setStatus(status promptStatus) {
+	p.HasStatus = true
+	p.Dirty, p.Ahead, p.Behind = status.Dirty, status.Ahead, status.Behind
+}
+
+// promptStatus runs git status in path, giving up after timeout. A runner
+// that takes a context has git killed then, so no status keeps running
+// behind the prompt.
+func (wm *WorktreeManager) promptStatus(path string, timeout time.Duration) (promptStatus, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	type result struct {
+		output string
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		statusCmd := fmt.Sprintf("git -C %s status --porcelain=v2 --branch", shellescape(path))
+		var r result
+		if runner, ok := wm.runner.(ContextCommandRunner); ok {
+			r.output, r.err = runner.RunContext(ctx, statusCmd)
+		} else {
+			r.output, r.err = wm.runner.Run(statusCmd)
+		}
+		done <- r
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return promptStatus{}, false
+		}
+		return parsePromptStatus(r.output), true
+	case <-ctx.Done():
+		return promptStatus{}, false
+	}
+}
+
+// promptLayout returns the layout configured in the config file content of
+// the git directory commonDir, as LoadLayout would without running git.
+func promptLayout(commonDir, config string, bare bool) (*Layout, error) {
+	repoPath, pathTemplate, newLayout := filepath.Dir(commonDir), DefaultPathTemplate, NewLayout
+	if bare {
+		repoPath, pathTemplate, newLayout = commonDir, DefaultBarePathTemplate, NewBareLayout
+	}
+	if configured := gitConfigValue(config, "wt", "pathTemplate"); configured != "" {
+		pathTemplate = configured
+	}
+	return newLayout(repoPath, pathTemplate)
+}
+
+// gitConfigValue returns the last value of section.key in the content of a
+// git config file. Section and key names are case-insensitive; subsections,
+// includes and line continuations are not supported.
+func gitConfigValue(config, section, key string) string {
+	var value, current string
+	for _, line := range strings.Split(config, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			current, _, _ = strings.Cut(strings.Trim(line, "[]"), " ")
+			continue
+		}
+		name, raw, ok := strings.Cut(line, "=")
+		if !ok || !strings.EqualFold(current, section) || !strings.EqualFold(strings.TrimSpace(name), key) {
+			continue
+		}
+		value = unquoteGitConfig(strings.TrimSpace(raw))
+	}
+	return value
+}
+
+// unquoteGitConfig strips the double quotes, escapes and trailing comment
+// of a git config value.
+func unquoteGitConfig(raw string) string {
+	var b strings.Builder
+	quoted := false
+	for i := 0; i < len(raw); i++ {
+		switch c := raw[i]; {
+		case c == '"':
+			quoted = !quoted
+		case c == '\\' && i+1 < len(raw):
+			i++
+			switch raw[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(raw[i])
+			}
+		case (c == '#' || c == ';') && !quoted:
+			return strings.TrimSpace(b.String())
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// parsePromptStatus parses the output of git status --porcelain=v2
+// --branch: headers start with #, every other line is a change.
+func parsePromptStatus(output string) promptStatus {
+	var status promptStatus
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "# branch.ab "):
+			for _, field := range strings.Fields(strings.TrimPrefix(line, "# branch.ab ")) {
+				n, _ := strconv.Atoi(field[1:])
+				if field[0] == '+' {
+					status.Ahead = n
+				} else {
+					status.Behind = n
+				}
+			}
+		case !strings.HasPrefix(line, "#"):
+			status.Dirty = true
+		}
+	}
+	return status
+}
+
+// findGitDir returns the worktree containing dir and its git directory,
+// following the gitdir: line of a linked worktree's .git file.
+func findGitDir(dir string) (string, string, bool) {
+	dir = filepath.Clean(dir)
+	for {
+		dotGit := filepath.Join(dir, ".git")
+		if stat, err := os.Stat(dotGit); err == nil {
+			if stat.IsDir() {
+				return dir, dotGit, true
+			}
+			content, err := os.ReadFile(dotGit)
+			if gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir: "); err == nil && ok {
+				if !filepath.IsAbs(gitDir) {
+					gitDir = filepath.Join(dir, gitDir)
+				}
+				return dir, filepath.Clean(gitDir), true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", false
+		}
+		dir = parent
+	}
+}
+
+func readPromptCache(path string) map[string]promptCacheEntry {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var cache map[string]promptCacheEntry
+	if json.Unmarshal(content, &cache) != nil {
+		return nil
+	}
+	return cache
+}
+
+func writePromptCache(path string, cache map[string]promptCacheEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	content, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	// Prompts of several shells may write at once; the last rename wins
+	tmp, err := os.CreateTemp(filepath.Dir(path), "prompt-*.tmp")
+	if err != nil {
+		return err
+	}
+	_, writeErr := tmp.Write(content)
+	closeErr := tmp.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
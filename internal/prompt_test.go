@@ -0,0 +1,270 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParsePromptStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   promptStatus
+	}{
+		{
+			name:   "clean without upstream",
+			output: "# branch.oid 3f2a9c1\n# branch.head main\n",
+		},
+		{
+			name:   "ahead and behind",
+			output: "# branch.oid 3f2a9c1\n# branch.head main\n# branch.upstream origin/main\n# branch.ab +2 -1\n",
+			want:   promptStatus{Ahead: 2, Behind: 1},
+		},
+		{
+			name:   "untracked file",
+			output: "# branch.oid 3f2a9c1\n# branch.head main\n? notes.txt\n",
+			want:   promptStatus{Dirty: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsePromptStatus(tt.output); got != tt.want {
+				t.Errorf("parsePromptStatus() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromptInfo_Markers(t *testing.T) {
+	info := PromptInfo{Dirty: true, Ahead: 2, Behind: 1}
+	if got, want := info.Markers(), "*↑2↓1"; got != want {
+		t.Errorf("Markers() = %q, want %q", got, want)
+	}
+	if got := (PromptInfo{}).Markers(); got != "" {
+		t.Errorf("Markers() = %q, want empty", got)
+	}
+}
+
+// writePromptRepo lays out the git files Prompt reads: a main worktree on
+// main, a linked worktree feat on feature/x and a detached one named review.
+func writePromptRepo(t *testing.T) string {
+	t.Helper()
+	repo := t.TempDir()
+	files := map[string]string{
+		".git/HEAD":                        "ref: refs/heads/main\n",
+		".git/config":                      "[core]\n\tbare = false\n",
+		".git/worktrees/feat/HEAD":         "ref: refs/heads/feature/x\n",
+		".git/worktrees/feat/commondir":    "../..\n",
+		".git/worktrees/review/HEAD":       "3f2a9c1d8e7b6a5f\n",
+		".git/worktrees/review/commondir":  "../..\n",
+		"worktrees/feat/.git":              "gitdir: " + filepath.Join(repo, ".git/worktrees/feat") + "\n",
+		"worktrees/review/.git":            "gitdir: ../../.git/worktrees/review\n",
+		"worktrees/feat/services/api/x.go": "package api\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+func TestWorktreeManager_Prompt(t *testing.T) {
+	repo := writePromptRepo(t)
+	manager := NewWorktreeManager(NewGitService(&MockCommandRunner{}), &MockCommandRunner{})
+
+	tests := []struct {
+		name string
+		dir  string
+		want *PromptInfo
+	}{
+		{
+			name: "main worktree",
+			dir:  repo,
+			want: &PromptInfo{Name: "main", Branch: "main", Path: repo, Main: true},
+		},
+		{
+			name: "subdirectory of a linked worktree",
+			dir:  filepath.Join(repo, "worktrees/feat/services/api"),
+			want: &PromptInfo{Name: "feat", Branch: "feature/x", Path: filepath.Join(repo, "worktrees/feat")},
+		},
+		{
+			name: "detached worktree",
+			dir:  filepath.Join(repo, "worktrees/review"),
+			want: &PromptInfo{Name: "review", Branch: "3f2a9c1", Path: filepath.Join(repo, "worktrees/review")},
+		},
+		{
+			name: "outside a repository",
+			dir:  t.TempDir(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := manager.Prompt(tt.dir, PromptOptions{})
+			if err != nil {
+				t.Fatalf("Prompt() error = %v", err)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("Prompt() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorktreeManager_Prompt_PathTemplate(t *testing.T) {
+	// A wt clone --bare layout, where the worktree review has another
+	// branch checked out than the one it was added for
+	dir := t.TempDir()
+	files := map[string]string{
+		".bare/HEAD":                       "ref: refs/heads/main\n",
+		".bare/config":                     "[core]\n\tbare = true\n[wt]\n\tpathTemplate = \"{{.RepoParent}}/{{.Name}}\"\n",
+		".bare/worktrees/review/HEAD":      "ref: refs/heads/feature/x\n",
+		".bare/worktrees/review/commondir": "../..\n",
+		".git":                             "gitdir: ./.bare\n",
+		"review/.git":                      "gitdir: ../.bare/worktrees/review\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manager := NewWorktreeManager(NewGitService(&MockCommandRunner{}), &MockCommandRunner{})
+
+	got, err := manager.Prompt(filepath.Join(dir, "review"), PromptOptions{})
+	if err != nil {
+		t.Fatalf("Prompt() error = %v", err)
+	}
+	want := &PromptInfo{Name: "review", Branch: "feature/x", Path: filepath.Join(dir, "review")}
+	if got == nil || *got != *want {
+		t.Errorf("Prompt() = %+v, want %+v", got, want)
+	}
+	// The clone's directory is no worktree
+	if got, _ := manager.Prompt(dir, PromptOptions{}); got != nil {
+		t.Errorf("Prompt() in the bare clone = %+v, want nil", got)
+	}
+}
+
+func TestGitConfigValue(t *testing.T) {
+	config := `[core]
+	bare = false
+[remote "origin"]
+	url = git@example.com:app.git
+[WT]
+	PathTemplate = worktrees/{{.Name}}
+	pathtemplate = "trees/{{.Branch}}" ; the last one wins
+`
+	if got := gitConfigValue(config, "wt", "pathTemplate"); got != "trees/{{.Branch}}" {
+		t.Errorf("gitConfigValue(wt.pathTemplate) = %q, want %q", got, "trees/{{.Branch}}")
+	}
+	if got := gitConfigValue(config, "core", "bare"); got != "false" {
+		t.Errorf("gitConfigValue(core.bare) = %q, want false", got)
+	}
+	if got := gitConfigValue(config, "core", "url"); got != "" {
+		t.Errorf("gitConfigValue(core.url) = %q, want empty", got)
+	}
+}
+
+func TestWorktreeManager_Prompt_Status(t *testing.T) {
+	repo := writePromptRepo(t)
+	feat := filepath.Join(repo, "worktrees/feat")
+	statusCmd := "git -C " + feat + " status --porcelain=v2 --branch"
+	mockRunner := &MockCommandRunner{outputs: map[string]string{
+		statusCmd: "# branch.head feature/x\n# branch.ab +2 -0\n1 .M N... 100644 100644 100644 a b x.go\n",
+	}}
+	manager := NewWorktreeManager(NewGitService(mockRunner), mockRunner)
+	opts := PromptOptions{Status: true, Timeout: time.Second, CacheTTL: time.Minute}
+
+	got, err := manager.Prompt(feat, opts)
+	if err != nil {
+		t.Fatalf("Prompt() error = %v", err)
+	}
+	if !got.HasStatus || got.Markers() != "*↑2" {
+		t.Fatalf("Prompt() = %+v, want dirty and 2 ahead", got)
+	}
+
+	// A cached status is reused without running git
+	delete(mockRunner.outputs, statusCmd)
+	if got, _ := manager.Prompt(feat, opts); !got.HasStatus || got.Markers() != "*↑2" {
+		t.Errorf("Prompt() with cache = %+v, want the cached status", got)
+	}
+
+	// Moving HEAD invalidates it
+	if err := os.WriteFile(filepath.Join(repo, ".git/worktrees/feat/HEAD"), []byte("ref: refs/heads/other\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := manager.Prompt(feat, opts); got.HasStatus {
+		t.Errorf("Prompt() after checkout = %+v, want no status", got)
+	}
+}
+
+// slowRunner answers every command after a delay.
+type slowRunner struct {
+	delay time.Duration
+}
+
+func (r slowRunner) Run(command string) (string, error) {
+	time.Sleep(r.delay)
+	return "", nil
+}
+
+func TestWorktreeManager_Prompt_Timeout(t *testing.T) {
+	repo := writePromptRepo(t)
+	runner := slowRunner{delay: time.Second}
+	manager := NewWorktreeManager(NewGitService(runner), runner)
+
+	start := time.Now()
+	got, err := manager.Prompt(repo, PromptOptions{Status: true, Timeout: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Prompt() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Prompt() took %v, want it to give up after the timeout", elapsed)
+	}
+	if got.HasStatus || got.Name != "main" {
+		t.Errorf("Prompt() = %+v, want main without status", got)
+	}
+}
+
+// blockingRunner runs every command until its context is done, and
+// reports that it was stopped.
+type blockingRunner struct {
+	stopped chan struct{}
+}
+
+func (r blockingRunner) Run(command string) (string, error) {
+	return r.RunContext(context.Background(), command)
+}
+
+func (r blockingRunner) RunContext(ctx context.Context, command string) (string, error) {
+	<-ctx.Done()
+	close(r.stopped)
+	return "", ctx.Err()
+}
+
+func TestWorktreeManager_Prompt_TimeoutStopsGit(t *testing.T) {
+	repo := writePromptRepo(t)
+	runner := blockingRunner{stopped: make(chan struct{})}
+	manager := NewWorktreeManager(NewGitService(runner), runner)
+
+	if _, err := manager.Prompt(repo, PromptOptions{Status: true, Timeout: 20 * time.Millisecond}); err != nil {
+		t.Fatalf("Prompt() error = %v", err)
+	}
+	select {
+	case <-runner.stopped:
+	case <-time.After(time.Second):
+		t.Error("git status was left running after the timeout")
+	}
+}
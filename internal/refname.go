@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// validateBranchName applies the rules of `git check-ref-format --branch`
+// so that invalid names are rejected with a precise message before any git
+// command runs.
+func validateBranchName(branch string) error {
+	if branch == "" {
+		return fmt.Errorf("branch name cannot be empty")
+	}
+
+	if strings.HasPrefix(branch, "-") {
+		return fmt.Errorf("branch name cannot start with dash: %q", branch)
+	}
+
+	// git accepts these as branch names, but they are shorthands for HEAD
+	// and wt reserves "@" for its own aliases.
+	if branch == "HEAD" || branch == "@" {
+		return fmt.Errorf("branch name cannot be %q", branch)
+	}
+
+	for i := 0; i < len(branch); i++ {
+		c := branch[i]
+		if c < 0x20 || c == 0x7f {
+			return fmt.Errorf("branch name cannot contain control characters: %q", branch)
+		}
+		switch c {
+		case ' ', '~', '^', ':', '?', '*', '[', '\\':
+			return fmt.Errorf("branch name cannot contain %q: %q", c, branch)
+		}
+	}
+
+	if strings.Contains(branch, "..") {
+		return fmt.Errorf("branch name cannot contain \"..\": %q", branch)
+	}
+
+	if strings.Contains(branch, "@{") {
+		return fmt.Errorf("branch name cannot contain \"@{\": %q", branch)
+	}
+
+	if strings.HasPrefix(branch, "/") || strings.HasSuffix(branch, "/") {
+		return fmt.Errorf("branch name cannot begin or end with a slash: %q", branch)
+	}
+
+	if strings.Contains(branch, "//") {
+		return fmt.Errorf("branch name cannot contain consecutive slashes: %q", branch)
+	}
+
+	if strings.HasSuffix(branch, ".") {
+		return fmt.Errorf("branch name cannot end with a dot: %q", branch)
+	}
+
+	for _, component := range strings.Split(branch, "/") {
+		if strings.HasPrefix(component, ".") {
+			return fmt.Errorf("branch name component cannot begin with a dot: %q", branch)
+		}
+		if strings.HasSuffix(component, ".lock") {
+			return fmt.Errorf("branch name component cannot end with \".lock\": %q", branch)
+		}
+	}
+
+	return nil
+}
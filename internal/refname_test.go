@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"math/rand"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+func TestValidateBranchName_Rules(t *testing.T) {
+	tests := []struct {
+		name    string
+		branch  string
+		wantErr string
+	}{
+		{name: "double dot", branch: "foo..bar", wantErr: `cannot contain ".."`},
+		{name: "lock suffix", branch: "foo.lock", wantErr: `cannot end with ".lock"`},
+		{name: "lock suffix in component", branch: "foo.lock/bar", wantErr: `cannot end with ".lock"`},
+		{name: "consecutive slashes", branch: "a//b", wantErr: "consecutive slashes"},
+		{name: "reflog syntax", branch: "x@{y}", wantErr: `cannot contain "@{"`},
+		{name: "leading slash", branch: "/feature", wantErr: "begin or end with a slash"},
+		{name: "trailing slash", branch: "feature/", wantErr: "begin or end with a slash"},
+		{name: "trailing dot", branch: "feature.", wantErr: "cannot end with a dot"},
+		{name: "hidden component", branch: "feature/.auth", wantErr: "cannot begin with a dot"},
+		{name: "open bracket", branch: "a[b", wantErr: `cannot contain '['`},
+		{name: "tilde", branch: "a~1", wantErr: `cannot contain '~'`},
+		{name: "caret", branch: "a^1", wantErr: `cannot contain '^'`},
+		{name: "colon", branch: "a:b", wantErr: `cannot contain ':'`},
+		{name: "question mark", branch: "a?", wantErr: `cannot contain '?'`},
+		{name: "asterisk", branch: "a*", wantErr: `cannot contain '*'`},
+		{name: "backslash", branch: `a\b`, wantErr: `cannot contain '\\'`},
+		{name: "control character", branch: "a\tb", wantErr: "control characters"},
+		{name: "HEAD", branch: "HEAD", wantErr: `cannot be "HEAD"`},
+		{name: "at sign", branch: "@", wantErr: `cannot be "@"`},
+		{name: "parentheses", branch: "fix(parser)"},
+		{name: "at sign inside name", branch: "user@host"},
+		{name: "non-ascii", branch: "feature/café"},
+		{name: "dots inside name", branch: "release/v1.2.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBranchName(tt.branch)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateBranchName(%q) unexpected error = %v", tt.branch, err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("validateBranchName(%q) expected error containing %q", tt.branch, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateBranchName(%q) error = %v, want error containing %q", tt.branch, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// branchCandidate generates short strings biased towards the characters
+// and sequences that matter for ref name validation.
+type branchCandidate string
+
+func (branchCandidate) Generate(r *rand.Rand, size int) reflect.Value {
+	pieces := []string{
+		"a", "b", "feature", "/", "/", ".", "..", "-", "_", "@", "{", "}",
+		"(", ")", "[", "~", "^", ":", "?", "*", "\\", " ", ";", ".lock",
+		"HEAD", "é", "\x01",
+	}
+	n := 1 + r.Intn(6)
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		b.WriteString(pieces[r.Intn(len(pieces))])
+	}
+	return reflect.ValueOf(branchCandidate(b.String()))
+}
+
+func TestValidateBranchName_WorktreePathProperty(t *testing.T) {
+	repoPath := "/repo"
+	worktreesDir := filepath.Join(repoPath, "worktrees")
+
+	property := func(c branchCandidate) bool {
+		branch := string(c)
+		if validateBranchName(branch) != nil {
+			return true
+		}
+
+		name := BranchToWorktreeName(branch)
+		if name == "" || name == "." || name == ".." || strings.Contains(name, "/") {
+			return false
+		}
+
+		// Every valid branch maps to a direct child of the worktrees directory.
+		path := GenerateWorktreePath(repoPath, branch)
+		return filepath.Dir(path) == worktreesDir && filepath.Base(path) == name
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidateBranchName_AgreesWithGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	property := func(c branchCandidate) bool {
+		branch := string(c)
+		// Names that wt reserves on top of git's rules.
+		if branch == "@" {
+			return true
+		}
+		gitErr := exec.Command("git", "check-ref-format", "--branch", branch).Run()
+		return (validateBranchName(branch) == nil) == (gitErr == nil)
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 300}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,188 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// maxRepairDepth limits how deep below the managed root RepairWorktrees
+// looks for worktrees, so unrelated directory trees are not walked.
+const maxRepairDepth = 6
+
+// RepairResult describes what RepairWorktrees found and fixed.
+type RepairResult struct {
+	// Repaired are the worktree paths whose links are intact again.
+	Repaired []string
+	// Broken describes the registered worktrees still not linked both ways.
+	Broken []string
+}
+
+// adminEntry is a linked worktree as recorded in the git directory.
+type adminEntry struct {
+	// Dir holds the administrative files, .git/worktrees/<id>.
+	Dir string
+	// Worktree is the path recorded in Dir/gitdir.
+	Worktree string
+}
+
+// RepairWorktrees fixes the links between the repository and its linked
+// worktrees after either side was moved by hand, such as when the
+// repository directory is renamed.
+//
+// Each worktree's .git file must point at its administrative files and
+// those must point back. Worktrees with a broken link, whether registered or
+// found in the managed root, are passed to git worktree repair; the links
+// are then checked again and any still broken are reported.
+func (wm *WorktreeManager) RepairWorktrees(repoPath string) (*RepairResult, error) {
+	if err := validatePath(repoPath); err != nil {
+		return nil, fmt.Errorf("invalid repository path: %w", err)
+	}
+
+	layout, err := LoadLayout(wm.runner, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	commonDir, err := wm.gitCommonDir(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := readAdminEntries(commonDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []string
+	for _, entry := range entries {
+		if _, err := os.Stat(entry.Worktree); err == nil && !linked(entry.Dir, entry.Worktree) {
+			candidates = append(candidates, entry.Worktree)
+		}
+	}
+	for _, dir := range findLinkedDirs(layout, layout.Root(), 0) {
+		adminDir, ok := linkedAdminDir(commonDir, dir)
+		if ok && !linked(adminDir, dir) && !slices.Contains(candidates, dir) {
+			candidates = append(candidates, dir)
+		}
+	}
+
+	result := &RepairResult{}
+	if len(candidates) > 0 {
+		args := make([]string, len(candidates))
+		for i, path := range candidates {
+			args[i] = shellescape(path)
+		}
+		repairCmd := fmt.Sprintf("git -C %s worktree repair %s", shellescape(repoPath), strings.Join(args, " "))
+		if _, err := wm.runner.Run(repairCmd); err != nil {
+			return nil, fmt.Errorf("git worktree repair failed: %w", err)
+		}
+	}
+
+	// Check both directions again rather than trusting the repair
+	if entries, err = readAdminEntries(commonDir); err != nil {
+		return nil, err
+	}
+	for _, path := range candidates {
+		if adminDir, ok := linkedAdminDir(commonDir, path); ok && linked(adminDir, path) {
+			result.Repaired = append(result.Repaired, path)
+		}
+	}
+	for _, entry := range entries {
+		if linked(entry.Dir, entry.Worktree) {
+			continue
+		}
+		reason := "its .git file does not point back"
+		if _, err := os.Stat(entry.Worktree); os.IsNotExist(err) {
+			reason = "directory is missing"
+		}
+		result.Broken = append(result.Broken, fmt.Sprintf("%s (%s)", entry.Worktree, reason))
+	}
+
+	return result, nil
+}
+
+// readAdminEntries lists the linked worktrees recorded in commonDir.
+func readAdminEntries(commonDir string) ([]adminEntry, error) {
+	dirs, err := os.ReadDir(filepath.Join(commonDir, "worktrees"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read worktree administrative files: %w", err)
+	}
+
+	var entries []adminEntry
+	for _, dir := range dirs {
+		adminDir := filepath.Join(commonDir, "worktrees", dir.Name())
+		content, err := os.ReadFile(filepath.Join(adminDir, "gitdir"))
+		if err != nil {
+			continue
+		}
+		// gitdir holds the path of the worktree's .git file
+		entries = append(entries, adminEntry{
+			Dir:      adminDir,
+			Worktree: filepath.Dir(strings.TrimSpace(string(content))),
+		})
+	}
+	return entries, nil
+}
+
+// linked reports whether the worktree at path and the administrative files
+// in adminDir point at each other.
+func linked(adminDir, path string) bool {
+	content, err := os.ReadFile(filepath.Join(adminDir, "gitdir"))
+	if err != nil || filepath.Clean(strings.TrimSpace(string(content))) != filepath.Join(path, ".git") {
+		return false
+	}
+	gitdir, err := readGitFile(path)
+	if err != nil {
+		return false
+	}
+	return filepath.Clean(gitdir) == filepath.Clean(adminDir)
+}
+
+// linkedAdminDir returns the administrative files in commonDir that the
+// worktree at path belongs to, judged by the name its .git file points at.
+// This still works when the repository has moved and the .git file holds
+// the old location.
+func linkedAdminDir(commonDir, path string) (string, bool) {
+	gitdir, err := readGitFile(path)
+	if err != nil || filepath.Base(filepath.Dir(gitdir)) != "worktrees" {
+		return "", false
+	}
+	adminDir := filepath.Join(commonDir, "worktrees", filepath.Base(gitdir))
+	if _, err := os.Stat(filepath.Join(adminDir, "gitdir")); err != nil {
+		return "", false
+	}
+	return adminDir, true
+}
+
+// findLinkedDirs returns the directories below dir that have a .git file,
+// without descending into them.
+func findLinkedDirs(layout *Layout, dir string, depth int) []string {
+	if depth >= maxRepairDepth {
+		return nil
+	}
+	children, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var found []string
+	for _, child := range children {
+		path := filepath.Join(dir, child.Name())
+		if !child.IsDir() || path == layout.RepoPath {
+			continue
+		}
+		if info, err := os.Lstat(filepath.Join(path, ".git")); err == nil {
+			if info.Mode().IsRegular() {
+				found = append(found, path)
+			}
+			continue
+		}
+		found = append(found, findLinkedDirs(layout, path, depth+1)...)
+	}
+	return found
+}
@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWorktreeManager_RepairWorktrees(t *testing.T) {
+	// writeLinks creates a worktree at path whose .git file names gitdir,
+	// and administrative files for id that name recorded as the worktree.
+	writeLinks := func(t *testing.T, commonDir, id, path, gitdir, recorded string) {
+		t.Helper()
+		adminDir := filepath.Join(commonDir, "worktrees", id)
+		for _, dir := range []string{adminDir, path} {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.WriteFile(filepath.Join(adminDir, "gitdir"), []byte(recorded+"/.git\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(path, ".git"), []byte("gitdir: "+gitdir+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	newRunner := func(repo string) *MockCommandRunner {
+		return &MockCommandRunner{outputs: map[string]string{
+			"git -C " + repo + " rev-parse --git-common-dir": ".git\n",
+		}}
+	}
+
+	t.Run("intact links are left alone", func(t *testing.T) {
+		repo := t.TempDir()
+		commonDir := filepath.Join(repo, ".git")
+		path := filepath.Join(repo, "worktrees", "feature")
+		writeLinks(t, commonDir, "feature", path, filepath.Join(commonDir, "worktrees", "feature"), path)
+
+		runner := newRunner(repo)
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		result, err := manager.RepairWorktrees(repo)
+		if err != nil {
+			t.Fatalf("RepairWorktrees() error = %v", err)
+		}
+		if len(result.Repaired) != 0 || len(result.Broken) != 0 {
+			t.Errorf("RepairWorktrees() = %+v, want nothing to do", result)
+		}
+		for _, cmd := range runner.GetCommands() {
+			if strings.Contains(cmd, "worktree repair") {
+				t.Errorf("unexpected repair: %s", cmd)
+			}
+		}
+	})
+
+	t.Run("worktrees of a renamed repository are repaired", func(t *testing.T) {
+		repo := t.TempDir()
+		commonDir := filepath.Join(repo, ".git")
+		path := filepath.Join(repo, "worktrees", "feature")
+		writeLinks(t, commonDir, "feature", path, "/old/app/.git/worktrees/feature", "/old/app/worktrees/feature")
+
+		runner := newRunner(repo)
+		repairCmd := "git -C " + repo + " worktree repair " + path
+		runner.outputs[repairCmd] = ""
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		result, err := manager.RepairWorktrees(repo)
+		if err != nil {
+			t.Fatalf("RepairWorktrees() error = %v (commands: %v)", err, runner.GetCommands())
+		}
+		if !slices.Contains(runner.GetCommands(), repairCmd) {
+			t.Errorf("worktree not passed to git worktree repair, commands: %v", runner.GetCommands())
+		}
+		// The mock does not fix anything, so the check afterwards fails
+		if len(result.Repaired) != 0 || len(result.Broken) != 1 || !strings.Contains(result.Broken[0], "directory is missing") {
+			t.Errorf("RepairWorktrees() = %+v, want the old entry reported as broken", result)
+		}
+	})
+}
@@ -0,0 +1,259 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Reserved names understood by Resolver. Worktree names never start with @
+// since git refuses branch names that are exactly @.
+const (
+	// AliasMain is the repository's primary worktree.
+	AliasMain = "@main"
+	// AliasCurrent is the worktree containing the working directory.
+	AliasCurrent = "@"
+	// AliasPrevious is the worktree used before the current one; @-2 is the
+	// one before that, and so on.
+	AliasPrevious = "@-"
+)
+
+// maxCandidates limits the worktrees listed for an ambiguous target.
+const maxCandidates = 5
+
+// Resolver finds the worktree meant by a target typed on the command line.
+// A target is tried, in order, as a reserved alias, a worktree name, a
+// branch name, a path, a unique prefix of a name or branch and, if Fuzzy is
+// set, a fuzzy match. Paths starting with ., .., / or ~ select the worktree
+// containing them; other relative paths must be a worktree's directory.
+type Resolver struct {
+	Worktrees []Worktree
+	// Dir is the working directory, for @ and relative paths.
+	Dir string
+	// History is the switch history, for @- and @-N.
+	History []HistoryEntry
+	// Fuzzy falls back to fuzzy matching over names and branches.
+	Fuzzy bool
+}
+
+// ResolveError reports a target matching no worktree, or several equally.
+type ResolveError struct {
+	Target string
+	// Ambiguous is set when every candidate matches the target.
+	Ambiguous bool
+	// Candidates are the worktrees the target may have meant, best first.
+	Candidates []Worktree
+	// Reason replaces the default message when set.
+	Reason string
+}
+
+func (e *ResolveError) Error() string {
+	if e.Reason != "" {
+		return e.Reason
+	}
+	if !e.Ambiguous {
+		return fmt.Sprintf("worktree %q not found", e.Target)
+	}
+	names := make([]string, len(e.Candidates))
+	for i, wt := range e.Candidates {
+		names[i] = wt.Name()
+	}
+	return fmt.Sprintf("%q matches more than one worktree: %s", e.Target, strings.Join(names, ", "))
+}
+
+// Resolve returns the worktree target refers to.
+func (r *Resolver) Resolve(target string) (*Worktree, error) {
+	if len(r.Worktrees) == 0 {
+		return nil, fmt.Errorf("no worktrees found")
+	}
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil, fmt.Errorf("target name cannot be empty")
+	}
+
+	switch target {
+	case AliasMain:
+		for i := range r.Worktrees {
+			if r.Worktrees[i].Main {
+				return &r.Worktrees[i], nil
+			}
+		}
+		return nil, &ResolveError{Target: target, Reason: "the repository has no main worktree"}
+	case AliasCurrent:
+		return r.byPath(target, r.Dir)
+	}
+	if n, ok := previousIndex(target); ok {
+		return r.previous(target, n)
+	}
+
+	if wt, err := r.unique(target, func(wt Worktree) bool { return wt.Name() == target }); wt != nil || err != nil {
+		return wt, err
+	}
+	branch := strings.TrimPrefix(target, "refs/heads/")
+	if wt, err := r.unique(target, func(wt Worktree) bool { return !wt.Detached() && wt.Branch == branch }); wt != nil || err != nil {
+		return wt, err
+	}
+
+	if looksLikePath(target) {
+		return r.byPath(target, target)
+	}
+	// Other relative paths must name a worktree itself, so a directory
+	// such as src does not quietly stand for the current worktree
+	if wt, err := r.byPath(target, target); err == nil && samePath(wt.Path, r.abs(target)) {
+		return wt, nil
+	}
+
+	if wt, err := r.unique(target, func(wt Worktree) bool {
+		return strings.HasPrefix(wt.Name(), target) || (!wt.Detached() && strings.HasPrefix(wt.Branch, target))
+	}); wt != nil || err != nil {
+		return wt, err
+	}
+
+	if r.Fuzzy {
+		if wt, err := r.fuzzy(target); wt != nil || err != nil {
+			return wt, err
+		}
+	}
+	return nil, r.notFound(target)
+}
+
+// previous returns the worktree used n switches before the current one.
+func (r *Resolver) previous(target string, n int) (*Worktree, error) {
+	var current string
+	if wt, ok := FindWorktreeByPath(r.Worktrees, r.Dir); ok {
+		current = wt.Path
+	}
+	recent := RecentWorktrees(r.Worktrees, r.History, current, false)
+	if n > len(recent) {
+		if len(recent) == 0 {
+			return nil, &ResolveError{Target: target, Reason: "no previous worktree"}
+		}
+		return nil, &ResolveError{Target: target, Reason: fmt.Sprintf("only %d previous worktree(s) in the history", len(recent))}
+	}
+	path := recent[n-1].Path
+	for i := range r.Worktrees {
+		if r.Worktrees[i].Path == path {
+			return &r.Worktrees[i], nil
+		}
+	}
+	return nil, &ResolveError{Target: target, Reason: "no previous worktree"}
+}
+
+// previousIndex parses @- and @-N, returning N.
+func previousIndex(target string) (int, bool) {
+	if target == AliasPrevious {
+		return 1, true
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(target, AliasPrevious))
+	if !strings.HasPrefix(target, AliasPrevious) || err != nil || n < 1 {
+		return 0, false
+	}
+	return n, true
+}
+
+// unique returns the only worktree matching, nil if none does, or an error
+// listing them if several do.
+func (r *Resolver) unique(target string, match func(Worktree) bool) (*Worktree, error) {
+	var found []Worktree
+	var first *Worktree
+	for i := range r.Worktrees {
+		if match(r.Worktrees[i]) {
+			if first == nil {
+				first = &r.Worktrees[i]
+			}
+			found = append(found, r.Worktrees[i])
+		}
+	}
+	switch {
+	case len(found) == 0:
+		return nil, nil
+	case len(found) == 1:
+		return first, nil
+	default:
+		return nil, &ResolveError{Target: target, Ambiguous: true, Candidates: limitCandidates(found)}
+	}
+}
+
+// byPath returns the innermost worktree containing path.
+func (r *Resolver) byPath(target, path string) (*Worktree, error) {
+	path = r.abs(path)
+	// git reports worktree paths with symlinks resolved
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	wt, ok := FindWorktreeByPath(r.Worktrees, path)
+	if !ok {
+		return nil, &ResolveError{Target: target, Reason: fmt.Sprintf("%s is not inside a worktree", path)}
+	}
+	return wt, nil
+}
+
+func (r *Resolver) abs(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.Dir, path)
+	}
+	return filepath.Clean(path)
+}
+
+// fuzzy returns the best fuzzy match if it is clearly the one meant, nil
+// if nothing matches, or an error listing the closest matches.
+func (r *Resolver) fuzzy(target string) (*Worktree, error) {
+	matches := MatchWorktrees(r.Worktrees, target)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	if Unambiguous(matches) {
+		return &matches[0].Worktree, nil
+	}
+	candidates := make([]Worktree, len(matches))
+	for i, match := range matches {
+		candidates[i] = match.Worktree
+	}
+	return nil, &ResolveError{Target: target, Ambiguous: true, Candidates: limitCandidates(candidates)}
+}
+
+// notFound reports that nothing matches target, suggesting names it may be
+// a typo of.
+func (r *Resolver) notFound(target string) error {
+	names := make([]string, len(r.Worktrees))
+	for i, wt := range r.Worktrees {
+		names[i] = wt.Name()
+	}
+	resolveErr := &ResolveError{Target: target}
+	for _, name := range SimilarNames(names, target) {
+		for _, wt := range r.Worktrees {
+			if wt.Name() == name {
+				resolveErr.Candidates = append(resolveErr.Candidates, wt)
+			}
+		}
+	}
+	resolveErr.Candidates = limitCandidates(resolveErr.Candidates)
+	return resolveErr
+}
+
+func limitCandidates(worktrees []Worktree) []Worktree {
+	if len(worktrees) > maxCandidates {
+		return worktrees[:maxCandidates]
+	}
+	return worktrees
+}
+
+func samePath(a, b string) bool {
+	if resolved, err := filepath.EvalSymlinks(b); err == nil {
+		b = resolved
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
+
+// looksLikePath reports whether target can only be meant as a path.
+func looksLikePath(target string) bool {
+	return target == "." || target == ".." || filepath.IsAbs(target) ||
+		strings.HasPrefix(target, "./") || strings.HasPrefix(target, "../") || strings.HasPrefix(target, "~/")
+}
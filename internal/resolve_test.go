@@ -0,0 +1,175 @@
+package internal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestResolver_Resolve(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := filepath.Join(root, "worktrees", "feature-auth")
+	if err := os.MkdirAll(filepath.Join(auth, "src"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	worktrees := []Worktree{
+		{Path: root, Branch: "develop", Root: root + "/worktrees", Main: true},
+		{Path: auth, Branch: "feature/auth", Root: root + "/worktrees"},
+		{Path: root + "/worktrees/fix-auth", Branch: "fix/auth", Root: root + "/worktrees"},
+		{Path: root + "/worktrees/hotfix-bug-123", Branch: "hotfix/bug-123", Root: root + "/worktrees"},
+		{Path: root + "/worktrees/spike", Branch: DetachedBranch, Root: root + "/worktrees"},
+	}
+	resolver := &Resolver{
+		Worktrees: worktrees,
+		Dir:       filepath.Join(auth, "src"),
+		History: []HistoryEntry{
+			{Path: auth},
+			{Path: root + "/worktrees/spike"},
+			{Path: root + "/worktrees/removed"},
+			{Path: root},
+		},
+		Fuzzy: true,
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		want   string
+	}{
+		{name: "exact name", target: "feature-auth", want: auth},
+		{name: "main worktree by name", target: "develop", want: root},
+		{name: "branch", target: "feature/auth", want: auth},
+		{name: "full branch ref", target: "refs/heads/fix/auth", want: root + "/worktrees/fix-auth"},
+		{name: "main alias", target: "@main", want: root},
+		{name: "current alias", target: "@", want: auth},
+		{name: "previous alias", target: "@-", want: root + "/worktrees/spike"},
+		{name: "earlier worktree", target: "@-2", want: root},
+		{name: "dot", target: ".", want: auth},
+		{name: "relative path", target: "../../..", want: root},
+		{name: "absolute path", target: auth + "/src", want: auth},
+		{name: "unique prefix", target: "hot", want: root + "/worktrees/hotfix-bug-123"},
+		{name: "branch prefix", target: "feature/", want: auth},
+		{name: "fuzzy", target: "hb123", want: root + "/worktrees/hotfix-bug-123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.Resolve(tt.target)
+			if err != nil {
+				t.Fatalf("Resolve(%q) error = %v", tt.target, err)
+			}
+			if got.Path != tt.want {
+				t.Errorf("Resolve(%q) = %s, want %s", tt.target, got.Path, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolver_ResolveErrors(t *testing.T) {
+	worktrees := []Worktree{
+		{Path: "/repo", Branch: "main", Root: "/repo/worktrees", Main: true},
+		{Path: "/repo/worktrees/feature-auth", Branch: "feature/auth", Root: "/repo/worktrees"},
+		{Path: "/repo/worktrees/fix-auth", Branch: "fix/auth", Root: "/repo/worktrees"},
+	}
+
+	tests := []struct {
+		name           string
+		resolver       Resolver
+		target         string
+		wantErr        string
+		wantCandidates []string
+	}{
+		{
+			name:     "no worktrees",
+			resolver: Resolver{},
+			target:   "anything",
+			wantErr:  "no worktrees found",
+		},
+		{
+			name:     "empty target",
+			resolver: Resolver{Worktrees: worktrees},
+			target:   " ",
+			wantErr:  "target name cannot be empty",
+		},
+		{
+			name:           "ambiguous prefix",
+			resolver:       Resolver{Worktrees: worktrees},
+			target:         "f",
+			wantErr:        `"f" matches more than one worktree: feature-auth, fix-auth`,
+			wantCandidates: []string{"feature-auth", "fix-auth"},
+		},
+		{
+			name:           "ambiguous fuzzy match",
+			resolver:       Resolver{Worktrees: worktrees, Fuzzy: true},
+			target:         "auth",
+			wantErr:        `"auth" matches more than one worktree: fix-auth, feature-auth`,
+			wantCandidates: []string{"fix-auth", "feature-auth"},
+		},
+		{
+			name:     "no fuzzy matching unless enabled",
+			resolver: Resolver{Worktrees: worktrees},
+			target:   "auth",
+			wantErr:  `worktree "auth" not found`,
+		},
+		{
+			name:           "typo",
+			resolver:       Resolver{Worktrees: worktrees, Fuzzy: true},
+			target:         "mian",
+			wantErr:        `worktree "mian" not found`,
+			wantCandidates: []string{"main"},
+		},
+		{
+			name:     "no previous worktree",
+			resolver: Resolver{Worktrees: worktrees},
+			target:   "@-",
+			wantErr:  "no previous worktree",
+		},
+		{
+			name:     "beyond the history",
+			resolver: Resolver{Worktrees: worktrees, History: []HistoryEntry{{Path: "/repo"}}},
+			target:   "@-2",
+			wantErr:  "only 1 previous worktree(s) in the history",
+		},
+		{
+			name:     "path outside every worktree",
+			resolver: Resolver{Worktrees: worktrees, Dir: "/repo"},
+			target:   "/elsewhere",
+			wantErr:  "/elsewhere is not inside a worktree",
+		},
+		{
+			name:     "directory that is not a worktree",
+			resolver: Resolver{Worktrees: worktrees, Dir: "/repo"},
+			target:   "worktrees",
+			wantErr:  `worktree "worktrees" not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.resolver.Resolve(tt.target)
+			if err == nil {
+				t.Fatalf("Resolve(%q) = %s, want error", tt.target, got.Path)
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("Resolve(%q) error = %q, want %q", tt.target, err, tt.wantErr)
+			}
+
+			var resolveErr *ResolveError
+			var candidates []string
+			if errors.As(err, &resolveErr) {
+				for _, wt := range resolveErr.Candidates {
+					candidates = append(candidates, wt.Name())
+				}
+			}
+			if !reflect.DeepEqual(candidates, tt.wantCandidates) {
+				t.Errorf("Resolve(%q) candidates = %v, want %v", tt.target, candidates, tt.wantCandidates)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// KeepSubdirConfigKey is the git config key deciding whether wt switch
+// keeps the subdirectory of the current worktree. It defaults to true.
+const KeepSubdirConfigKey = "wt.keepSubdir"
+
+// KeepSubdir reports whether switching should keep the subdirectory, as
+// configured for repoPath.
+func (wm *WorktreeManager) KeepSubdir(repoPath string) bool {
+	configCmd := fmt.Sprintf("git -C %s config --type=bool --get %s", shellescape(repoPath), KeepSubdirConfigKey)
+	output, err := wm.runner.Run(configCmd)
+	return err != nil || strings.TrimSpace(output) != "false"
+}
+
+// EquivalentPath returns the directory in the worktree at target that
+// corresponds to dir in the worktree at current. If it does not exist in
+// target, its deepest existing ancestor is returned; if dir is not inside
+// current, target itself.
+func EquivalentPath(current, dir, target string) string {
+	rel, ok := relativeTo(current, dir)
+	if !ok {
+		return target
+	}
+
+	path := filepath.Join(target, rel)
+	for path != filepath.Clean(target) {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			return path
+		}
+		path = filepath.Dir(path)
+	}
+	return path
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEquivalentPath(t *testing.T) {
+	root := t.TempDir()
+	current := filepath.Join(root, "main")
+	target := filepath.Join(root, "feature")
+	for _, dir := range []string{
+		"main/services/api/handlers",
+		"feature/services/api",
+		"feature/docs",
+	} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A file where the directory would be is not entered
+	if err := os.WriteFile(filepath.Join(target, "README"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		dir  string
+		want string
+	}{
+		{name: "existing directory", dir: "main/services/api", want: "feature/services/api"},
+		{name: "deepest existing ancestor", dir: "main/services/api/handlers", want: "feature/services/api"},
+		{name: "nothing in common", dir: "main/tmp/scratch", want: "feature"},
+		{name: "file in the way", dir: "main/README", want: "feature"},
+		{name: "worktree root", dir: "main", want: "feature"},
+		{name: "outside the worktree", dir: "elsewhere", want: "feature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EquivalentPath(current, filepath.Join(root, tt.dir), target)
+			if want := filepath.Join(root, tt.want); got != want {
+				t.Errorf("EquivalentPath() = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestWorktreeManager_KeepSubdir(t *testing.T) {
+	configCmd := "git -C /repo config --type=bool --get " + KeepSubdirConfigKey
+	tests := []struct {
+		name    string
+		outputs map[string]string
+		want    bool
+	}{
+		{name: "unset", outputs: map[string]string{}, want: true},
+		{name: "enabled", outputs: map[string]string{configCmd: "true\n"}, want: true},
+		{name: "disabled", outputs: map[string]string{configCmd: "false\n"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &MockCommandRunner{outputs: tt.outputs}
+			manager := NewWorktreeManager(NewGitService(runner), runner)
+			if got := manager.KeepSubdir("/repo"); got != tt.want {
+				t.Errorf("KeepSubdir() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -13,24 +13,79 @@ const (
 	StatusStale
 )
 
+// DetachedBranch is the Branch of a worktree with a detached HEAD.
+const DetachedBranch = "detached HEAD"
+
 type Worktree struct {
 	Branch string
 	Path   string
 	Head   string
 	Status Status
+	// Root is the managed root of the repository's layout, if known.
+	Root string
+	// Bare marks the entry git lists for a bare repository itself; it has
+	// no working tree.
+	Bare bool
+	// Main marks the repository's primary worktree, the one git worktree
+	// list shows first unless the repository is bare.
+	Main bool
+	// Locked is set for worktrees locked with git worktree lock.
+	Locked     bool
+	LockReason string
 }
 
 func (w Worktree) IsClean() bool {
 	return w.Status == StatusClean
 }
 
+// Detached reports whether the worktree has no branch checked out.
+func (w Worktree) Detached() bool {
+	return w.Branch == DetachedBranch
+}
+
+// Name returns the worktree's name: its path relative to the managed root
+// for managed worktrees, otherwise derived from its branch.
 func (w Worktree) Name() string {
-	if strings.Contains(w.Path, "/worktrees/") {
+	if w.Root != "" {
+		if rel, ok := relativeTo(w.Root, w.Path); ok {
+			return BranchToWorktreeName(filepath.ToSlash(rel))
+		}
+		return BranchToWorktreeName(w.Branch)
+	}
+	// Without a known root, assume the default layout.
+	if filepath.Base(filepath.Dir(w.Path)) == "worktrees" {
 		return filepath.Base(w.Path)
 	}
 	return BranchToWorktreeName(w.Branch)
 }
 
+// FindWorktreeByPath returns the worktree containing dir. Nested worktrees
+// are resolved to the innermost one.
+func FindWorktreeByPath(worktrees []Worktree, dir string) (*Worktree, bool) {
+	var found *Worktree
+	for i := range worktrees {
+		wt := &worktrees[i]
+		if wt.Bare {
+			continue
+		}
+		if _, inside := relativeTo(wt.Path, dir); !inside && filepath.Clean(dir) != filepath.Clean(wt.Path) {
+			continue
+		}
+		if found == nil || len(wt.Path) > len(found.Path) {
+			found = wt
+		}
+	}
+	return found, found != nil
+}
+
 func BranchToWorktreeName(branch string) string {
 	return strings.ReplaceAll(branch, "/", "-")
 }
+
+// ShortCommit abbreviates a commit hash for display.
+func ShortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
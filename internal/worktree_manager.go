@@ -21,6 +21,12 @@ func NewWorktreeManager(gitService *GitService, runner CommandRunner) *WorktreeM
 }
 
 func (wm *WorktreeManager) AddWorktree(repoPath, branch string) (string, error) {
+	return wm.AddWorktreeFrom(repoPath, branch, "")
+}
+
+// AddWorktreeFrom adds a worktree like AddWorktree, creating branch at
+// startPoint. A non-empty startPoint requires the branch to be new.
+func (wm *WorktreeManager) AddWorktreeFrom(repoPath, branch, startPoint string) (string, error) {
 	if err := validateBranchName(branch); err != nil {
 		return "", err
 	}
@@ -29,126 +35,438 @@ func (wm *WorktreeManager) AddWorktree(repoPath, branch string) (string, error)
 		return "", fmt.Errorf("invalid repository path: %w", err)
 	}
 
-	worktreePath := GenerateWorktreePath(repoPath, branch)
-	worktreesDir := filepath.Dir(worktreePath)
+	layout, err := LoadLayout(wm.runner, repoPath)
+	if err != nil {
+		return "", err
+	}
+
+	worktreePath, err := layout.WorktreePath(branch)
+	if err != nil {
+		return "", err
+	}
+
+	// Record the state we are about to change so a failure leaves the
+	// repository exactly as we found it.
+	tx := newAddTransaction(wm.runner, repoPath)
+	tx.recordDirs(worktreePath)
+	if _, ok := layout.GitignoreEntry(); ok {
+		if err := tx.recordGitignore(filepath.Join(layout.RepoPath, ".gitignore")); err != nil {
+			return "", fmt.Errorf("failed to read .gitignore: %w", err)
+		}
+	}
 
-	if err := wm.ensureWorktreesDirectory(worktreesDir); err != nil {
-		return "", fmt.Errorf("failed to create worktrees directory: %w", err)
+	if err := wm.ensureWorktreesDirectory(layout, filepath.Dir(worktreePath)); err != nil {
+		return "", tx.fail(fmt.Errorf("failed to create worktrees directory: %w", err))
 	}
 
-	if err := wm.addGitWorktree(repoPath, worktreePath, branch); err != nil {
-		return "", fmt.Errorf("failed to add worktree: %w", err)
+	if err := wm.addGitWorktree(repoPath, worktreePath, branch, startPoint, tx); err != nil {
+		return "", tx.fail(fmt.Errorf("failed to add worktree: %w", err))
 	}
 
 	return worktreePath, nil
 }
 
 func (wm *WorktreeManager) RemoveWorktree(repoPath, name string) error {
+	_, err := wm.RemoveWorktrees(repoPath, []string{name}, RemoveOptions{})
+	return err
+}
+
+// RemoveMultipleWorktrees removes multiple worktrees in a single operation
+// with the default options.
+func (wm *WorktreeManager) RemoveMultipleWorktrees(repoPath string, names []string) error {
+	_, err := wm.RemoveWorktrees(repoPath, names, RemoveOptions{})
+	return err
+}
+
+// RemoveOptions controls how RemoveWorktrees treats its targets.
+type RemoveOptions struct {
+	// Force removes worktrees with uncommitted changes. The changes,
+	// including untracked files, are saved to a backup first; if that
+	// fails nothing is removed.
+	Force bool
+
+	// DeleteBranch deletes the branch of each removed worktree if it is
+	// merged into the base branch. ForceDeleteBranch deletes it regardless.
+	DeleteBranch      bool
+	ForceDeleteBranch bool
+
+	// KeepGoing attempts every target even if others fail, instead of
+	// restoring the worktrees already removed.
+	KeepGoing bool
+
+	// DryRun validates the targets and reports them without removing
+	// anything.
+	DryRun bool
+
+	// Archive allows removing worktrees whose HEAD has commits that are
+	// neither in its upstream nor, without one, in the base branch. Those
+	// commits are written to a git bundle first.
+	Archive bool
+}
+
+// RemoveResult describes the outcome for one target of RemoveWorktrees.
+type RemoveResult struct {
+	Name string
+	Path string
+	// Branch is empty for a detached worktree.
+	Branch string
+	// BackupRef holds the uncommitted changes saved by a forced removal.
+	BackupRef string
+	// Removed is set once the worktree is gone.
+	Removed bool
+	// BranchDeleted is set once Branch has been deleted.
+	BranchDeleted bool
+	// ArchivePath is the bundle holding the unpushed commits, if any.
+	ArchivePath string
+	// Err is why this target failed.
+	Err error
+
+	// head is the commit a detached worktree is restored at.
+	head       string
+	locked     bool
+	lockReason string
+	unpushed   unpushedCommits
+}
+
+// RemoveWorktrees removes the named worktrees in a single operation.
+//
+// It validates all worktrees upfront before removing any (fail-fast). If a
+// removal still fails, the worktrees already removed are added back at the
+// same path and branch, with their backed up changes applied, so either all
+// succeed or all fail. With KeepGoing every valid target is attempted and the
+// error summarizes the failures; the results report each target either way.
+func (wm *WorktreeManager) RemoveWorktrees(repoPath string, names []string, opts RemoveOptions) ([]RemoveResult, error) {
 	if err := validatePath(repoPath); err != nil {
-		return fmt.Errorf("invalid repository path: %w", err)
+		return nil, fmt.Errorf("invalid repository path: %w", err)
 	}
 
-	if name == "" {
-		return fmt.Errorf("worktree name cannot be empty")
+	if len(names) == 0 {
+		return nil, fmt.Errorf("at least one worktree name is required")
+	}
+
+	layout, err := LoadLayout(wm.runner, repoPath)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get all worktrees to find the target
+	// Get all worktrees once for efficiency (avoids repeated ListWorktrees calls)
 	worktrees, err := wm.gitService.ListWorktrees()
 	if err != nil {
-		return fmt.Errorf("failed to list worktrees: %w", err)
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
-	var targetWorktree *Worktree
-	for _, wt := range worktrees {
-		if wt.Name() == name {
-			targetWorktree = &wt
-			break
+	// Create lookup map for O(1) name resolution
+	worktreeMap := make(map[string]*Worktree)
+	for i := range worktrees {
+		worktreeMap[worktrees[i].Name()] = &worktrees[i]
+	}
+
+	// The base branch is needed to delete branches; otherwise it is only
+	// the fallback for finding unpushed commits
+	deleteBranches := opts.DeleteBranch || opts.ForceDeleteBranch
+	base, err := wm.baseBranch(repoPath)
+	if err != nil && deleteBranches {
+		return nil, err
+	}
+
+	// Phase 1: Validate all targets before removing any (fail-fast strategy)
+	// This ensures we don't end up in a partial removal state
+	results := make([]RemoveResult, len(names))
+	targets := make([]*Worktree, len(names))
+	for i, name := range names {
+		results[i].Name = name
+		target, unpushed, err := wm.validateRemoval(layout, worktreeMap, name, base, opts)
+		if err != nil {
+			if !opts.KeepGoing {
+				return nil, err
+			}
+			results[i].Err = err
+			continue
+		}
+		targets[i] = target
+		results[i] = RemoveResult{
+			Name:       name,
+			Path:       target.Path,
+			Branch:     target.Branch,
+			head:       target.Head,
+			locked:     target.Locked,
+			lockReason: target.LockReason,
+			unpushed:   unpushed,
+		}
+		if target.Detached() {
+			results[i].Branch = ""
 		}
 	}
 
-	if targetWorktree == nil {
-		return fmt.Errorf("worktree %q not found", name)
+	if opts.DryRun {
+		return results, nil
 	}
 
-	// Safety check: don't remove the main worktree
-	if !strings.Contains(targetWorktree.Path, "/worktrees/") {
-		return fmt.Errorf("cannot remove main worktree %q", name)
+	// Phase 2: Archive unpushed commits. Nothing has been changed yet, so a
+	// failure only has to skip the target.
+	for i, target := range targets {
+		if target == nil || results[i].unpushed.Count == 0 {
+			continue
+		}
+		archive, err := wm.archiveUnpushed(repoPath, results[i], results[i].unpushed)
+		if err != nil {
+			err = fmt.Errorf("failed to archive worktree %q: %w", results[i].Name, err)
+			if !opts.KeepGoing {
+				return nil, err
+			}
+			results[i].Err = err
+			targets[i] = nil
+			continue
+		}
+		results[i].ArchivePath = archive
+	}
+
+	// Phase 3: Save uncommitted changes of forced targets. If any backup
+	// fails, the changes already stashed are put back and nothing is removed.
+	for i, target := range targets {
+		if target == nil || target.Status != StatusDirty {
+			continue
+		}
+		ref, err := wm.backupChanges(repoPath, target.Path, results[i].Name)
+		results[i].BackupRef = ref
+		if err == nil {
+			continue
+		}
+
+		err = fmt.Errorf("failed to back up worktree %q: %w", results[i].Name, err)
+		if !opts.KeepGoing {
+			if restoreErr := wm.restoreBackups(results[:i+1]); restoreErr != nil {
+				return nil, fmt.Errorf("%w (%v)", err, restoreErr)
+			}
+			return nil, err
+		}
+		if restoreErr := wm.restoreBackups(results[i : i+1]); restoreErr != nil {
+			err = fmt.Errorf("%w (%v)", err, restoreErr)
+		}
+		results[i].Err = err
+		targets[i] = nil
 	}
 
-	// Safety check: warn if worktree has uncommitted changes
-	if targetWorktree.Status == StatusDirty {
-		return fmt.Errorf("worktree %q has uncommitted changes, commit or stash them first", name)
+	// Phase 4: All validations passed, execute removals
+	for i, target := range targets {
+		if target == nil {
+			continue
+		}
+		if err := wm.removeGitWorktree(repoPath, target.Path, results[i].BackupRef != ""); err != nil {
+			results[i].Err = fmt.Errorf("failed to remove worktree %q: %w", results[i].Name, err)
+			if !opts.KeepGoing {
+				return results, wm.undoRemovals(repoPath, results, results[i].Err)
+			}
+			// The worktree is still there, so its changes belong back in it
+			if restoreErr := wm.restoreBackups(results[i : i+1]); restoreErr != nil {
+				results[i].Err = fmt.Errorf("%w (%v)", results[i].Err, restoreErr)
+			}
+			continue
+		}
+		results[i].Removed = true
+	}
+
+	// Phase 5: Delete branches once their worktrees are gone, since git
+	// refuses to delete a checked out branch
+	if deleteBranches {
+		for i := range results {
+			if !results[i].Removed || results[i].Branch == "" {
+				continue
+			}
+			branchCmd := fmt.Sprintf("git -C %s branch -D %s", shellescape(repoPath), shellescape(results[i].Branch))
+			if _, err := wm.runner.Run(branchCmd); err != nil {
+				results[i].Err = fmt.Errorf("failed to delete branch %q: %w", results[i].Branch, err)
+				if !opts.KeepGoing {
+					wm.journalRemovals(repoPath, results)
+					return results, results[i].Err
+				}
+				continue
+			}
+			results[i].BranchDeleted = true
+		}
 	}
 
-	if err := wm.removeGitWorktree(repoPath, targetWorktree.Path); err != nil {
-		return fmt.Errorf("failed to remove worktree: %w", err)
+	wm.journalRemovals(repoPath, results)
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d worktrees failed", failed, len(results))
 	}
 
-	return nil
+	return results, nil
 }
 
-// RemoveMultipleWorktrees removes multiple worktrees in a single operation.
-// It validates all worktrees upfront before removing any, ensuring atomic behavior
-// (either all succeed or all fail). This prevents partial removal states.
-func (wm *WorktreeManager) RemoveMultipleWorktrees(repoPath string, names []string) error {
-	if err := validatePath(repoPath); err != nil {
-		return fmt.Errorf("invalid repository path: %w", err)
+// journalRemovals records the removed worktrees of results so they can be
+// restored. The worktrees are gone either way, so a failure only warns.
+func (wm *WorktreeManager) journalRemovals(repoPath string, results []RemoveResult) {
+	if err := wm.recordRemovals(repoPath, results); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record removal for wt restore: %v\n", err)
 	}
+}
 
-	if len(names) == 0 {
-		return fmt.Errorf("at least one worktree name is required")
+// validateRemoval looks up name and applies the checks of phase 1. base may
+// be empty unless branches are to be deleted.
+func (wm *WorktreeManager) validateRemoval(layout *Layout, worktreeMap map[string]*Worktree, name, base string, opts RemoveOptions) (*Worktree, unpushedCommits, error) {
+	if name == "" {
+		return nil, unpushedCommits{}, fmt.Errorf("worktree name cannot be empty")
 	}
 
-	// Get all worktrees once for efficiency (avoids repeated ListWorktrees calls)
-	worktrees, err := wm.gitService.ListWorktrees()
-	if err != nil {
-		return fmt.Errorf("failed to list worktrees: %w", err)
+	target, exists := worktreeMap[name]
+	if !exists {
+		return nil, unpushedCommits{}, fmt.Errorf("worktree %q not found", name)
 	}
 
-	// Create lookup map for O(1) name resolution
-	worktreeMap := make(map[string]*Worktree)
-	for i := range worktrees {
-		worktreeMap[worktrees[i].Name()] = &worktrees[i]
+	if err := checkRemovable(layout, target, name, opts); err != nil {
+		return nil, unpushedCommits{}, err
 	}
 
-	// Phase 1: Validate all targets before removing any (fail-fast strategy)
-	// This ensures we don't end up in a partial removal state
-	var targetsToRemove []*Worktree
-	for _, name := range names {
-		if name == "" {
-			return fmt.Errorf("worktree name cannot be empty")
+	if (opts.DeleteBranch || opts.ForceDeleteBranch) && !target.Detached() {
+		if err := wm.checkBranchDeletable(layout.RepoPath, target.Branch, base, opts.ForceDeleteBranch); err != nil {
+			return nil, unpushedCommits{}, err
 		}
+	}
 
-		targetWorktree, exists := worktreeMap[name]
-		if !exists {
-			return fmt.Errorf("worktree %q not found", name)
-		}
+	// Safety: commits that exist only here would be hard to find again
+	unpushed, err := wm.countUnpushed(target, base)
+	if err != nil {
+		return nil, unpushedCommits{}, fmt.Errorf("cannot check worktree %q for unpushed commits: %w", name, err)
+	}
+	if unpushed.Count > 0 && !opts.Archive {
+		return nil, unpushedCommits{}, fmt.Errorf("worktree %q has %d commit(s) not in %s, push them first (or use --archive to save them and remove)", name, unpushed.Count, unpushed.Reference)
+	}
 
-		// Safety: Only remove worktrees in the managed worktrees/ directory
-		if !strings.Contains(targetWorktree.Path, "/worktrees/") {
-			return fmt.Errorf("cannot remove main worktree %q", name)
+	return target, unpushed, nil
+}
+
+// undoRemovals adds the removed worktrees of results back at their old path
+// and branch, then applies every backup, and returns cause annotated with
+// the outcome.
+func (wm *WorktreeManager) undoRemovals(repoPath string, results []RemoveResult, cause error) error {
+	var problems []string
+	for i := len(results) - 1; i >= 0; i-- {
+		if !results[i].Removed {
+			continue
 		}
+		if err := wm.restoreWorktree(repoPath, results[i]); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", results[i].Name, err))
+			continue
+		}
+		results[i].Removed = false
+	}
 
-		// Safety: Prevent accidental data loss from uncommitted changes
-		if targetWorktree.Status == StatusDirty {
-			return fmt.Errorf("worktree %q has uncommitted changes, commit or stash them first", name)
+	var present []RemoveResult
+	for _, result := range results {
+		if !result.Removed {
+			present = append(present, result)
 		}
+	}
+	if err := wm.restoreBackups(present); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w (restore incomplete: %s)", cause, strings.Join(problems, "; "))
+	}
+	return fmt.Errorf("%w; worktrees already removed were restored", cause)
+}
 
-		targetsToRemove = append(targetsToRemove, targetWorktree)
+func (wm *WorktreeManager) restoreWorktree(repoPath string, result RemoveResult) error {
+	gitCmd := fmt.Sprintf("git -C %s worktree add %s %s",
+		shellescape(repoPath),
+		shellescape(result.Path),
+		shellescape(result.Branch))
+	if result.Branch == "" {
+		gitCmd = fmt.Sprintf("git -C %s worktree add --detach %s %s",
+			shellescape(repoPath),
+			shellescape(result.Path),
+			shellescape(result.head))
+	}
+	if _, err := wm.runner.Run(gitCmd); err != nil {
+		return fmt.Errorf("failed to add worktree back: %w", err)
 	}
+	return nil
+}
+
+// BaseBranchConfigKey is the git config key naming the branch that other
+// branches are merged into. It defaults to the branch checked out in the
+// main worktree, or HEAD of a bare repository.
+const BaseBranchConfigKey = "wt.baseBranch"
 
-	// Phase 2: All validations passed, execute removals
-	// If any removal fails, some worktrees will be removed and some won't
-	for _, target := range targetsToRemove {
-		if err := wm.removeGitWorktree(repoPath, target.Path); err != nil {
-			return fmt.Errorf("failed to remove worktree %q: %w", target.Name(), err)
+func (wm *WorktreeManager) baseBranch(repoPath string) (string, error) {
+	configCmd := fmt.Sprintf("git -C %s config --get %s", shellescape(repoPath), BaseBranchConfigKey)
+	if output, err := wm.runner.Run(configCmd); err == nil {
+		if branch := strings.TrimSpace(output); branch != "" {
+			return branch, nil
 		}
 	}
 
+	output, err := wm.runner.Run(fmt.Sprintf("git -C %s symbolic-ref --short HEAD", shellescape(repoPath)))
+	branch := strings.TrimSpace(output)
+	if err != nil || branch == "" {
+		return "", fmt.Errorf("cannot determine the base branch, set it with git config %s <branch>", BaseBranchConfigKey)
+	}
+	return branch, nil
+}
+
+// checkBranchDeletable reports whether branch may be deleted with its
+// worktree. Unless force is set, the branch must be merged into base.
+func (wm *WorktreeManager) checkBranchDeletable(repoPath, branch, base string, force bool) error {
+	if branch == base {
+		return fmt.Errorf("cannot delete base branch %q", branch)
+	}
+	if force {
+		return nil
+	}
+
+	ancestorCmd := fmt.Sprintf("git -C %s merge-base --is-ancestor %s %s",
+		shellescape(repoPath),
+		shellescape("refs/heads/"+branch),
+		shellescape("refs/heads/"+base))
+	if _, err := wm.runner.Run(ancestorCmd); err != nil {
+		return fmt.Errorf("branch %q is not merged into %s (use --force-delete-branch to delete it anyway)", branch, base)
+	}
+	return nil
+}
+
+// checkRemovable applies the safety checks shared by every removal path.
+func checkRemovable(layout *Layout, wt *Worktree, name string, opts RemoveOptions) error {
+	// Safety: never remove the main worktree
+	if filepath.Clean(wt.Path) == layout.RepoPath {
+		return fmt.Errorf("cannot remove main worktree %q", name)
+	}
+
+	// Safety: only remove worktrees inside the managed root
+	if !layout.IsManaged(wt.Path) {
+		return fmt.Errorf("cannot remove worktree %q outside the managed directory %s", name, layout.Root())
+	}
+
+	// Safety: prevent accidental data loss from uncommitted changes
+	if wt.Status == StatusDirty && !opts.Force {
+		return fmt.Errorf("worktree %q has uncommitted changes, commit or stash them first (or use --force to back them up and remove)", name)
+	}
+
 	return nil
 }
 
-func (wm *WorktreeManager) ensureWorktreesDirectory(worktreesDir string) error {
+// RemovableWorktrees returns the worktrees that pass the safety checks of
+// removal with opts. Locked worktrees are left out too, as git refuses to
+// remove them.
+func RemovableWorktrees(layout *Layout, worktrees []Worktree, opts RemoveOptions) []Worktree {
+	var removable []Worktree
+	for _, wt := range worktrees {
+		if !wt.Locked && checkRemovable(layout, &wt, wt.Name(), opts) == nil {
+			removable = append(removable, wt)
+		}
+	}
+	return removable
+}
+
+func (wm *WorktreeManager) ensureWorktreesDirectory(layout *Layout, worktreesDir string) error {
 	// Try Go standard library first, fallback to command if needed for compatibility
 	if err := os.MkdirAll(worktreesDir, 0o755); err != nil {
 		// Fallback to command runner for existing tests compatibility
@@ -158,35 +476,36 @@ func (wm *WorktreeManager) ensureWorktreesDirectory(worktreesDir string) error {
 		}
 	}
 
-	// Auto-setup .gitignore entry for worktrees directory
-	repoRoot := filepath.Dir(worktreesDir)
-	if err := wm.ensureGitignoreEntry(repoRoot); err != nil {
-		// Don't fail the operation if .gitignore setup fails, just warn
-		fmt.Printf("Warning: failed to setup .gitignore entry: %v\n", err)
+	// Auto-setup .gitignore entry when the managed root is inside the repository
+	if entry, ok := layout.GitignoreEntry(); ok {
+		if err := wm.ensureGitignoreEntry(layout.RepoPath, entry); err != nil {
+			// Don't fail the operation if .gitignore setup fails, just warn
+			fmt.Fprintf(os.Stderr, "Warning: failed to setup .gitignore entry: %v\n", err)
+		}
 	}
 
 	return nil
 }
 
-func (wm *WorktreeManager) ensureGitignoreEntry(repoRoot string) error {
+func (wm *WorktreeManager) ensureGitignoreEntry(repoRoot, entry string) error {
 	gitignorePath := filepath.Join(repoRoot, ".gitignore")
 
-	// Read .gitignore file and check if worktrees/ entry exists
+	// Read .gitignore file and check if the entry exists
 	file, err := os.Open(gitignorePath)
 	if err != nil {
-		// If .gitignore doesn't exist, create it with worktrees/ entry
+		// If .gitignore doesn't exist, create it with the entry
 		if os.IsNotExist(err) {
-			return wm.createGitignoreWithEntry(gitignorePath)
+			return wm.createGitignoreWithEntry(gitignorePath, entry)
 		}
 		return fmt.Errorf("failed to open .gitignore: %w", err)
 	}
 	defer file.Close()
 
-	// Check each line for worktrees/ entry
+	// Check each line for the entry
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
-		if line == "worktrees/" {
+		if line == entry {
 			// Entry already exists
 			return nil
 		}
@@ -197,45 +516,54 @@ func (wm *WorktreeManager) ensureGitignoreEntry(repoRoot string) error {
 	}
 
 	// Entry not found, add it
-	return wm.appendToGitignore(gitignorePath)
+	return wm.appendToGitignore(gitignorePath, entry)
 }
 
-func (wm *WorktreeManager) createGitignoreWithEntry(gitignorePath string) error {
+func (wm *WorktreeManager) createGitignoreWithEntry(gitignorePath, entry string) error {
 	file, err := os.Create(gitignorePath)
 	if err != nil {
 		return fmt.Errorf("failed to create .gitignore: %w", err)
 	}
 	defer file.Close()
 
-	if _, err := file.WriteString("worktrees/\n"); err != nil {
+	if _, err := file.WriteString(entry + "\n"); err != nil {
 		return fmt.Errorf("failed to write to .gitignore: %w", err)
 	}
 
 	return nil
 }
 
-func (wm *WorktreeManager) appendToGitignore(gitignorePath string) error {
+func (wm *WorktreeManager) appendToGitignore(gitignorePath, entry string) error {
 	file, err := os.OpenFile(gitignorePath, os.O_APPEND|os.O_WRONLY, 0o644)
 	if err != nil {
 		return fmt.Errorf("failed to open .gitignore for append: %w", err)
 	}
 	defer file.Close()
 
-	if _, err := file.WriteString("worktrees/\n"); err != nil {
+	if _, err := file.WriteString(entry + "\n"); err != nil {
 		return fmt.Errorf("failed to append to .gitignore: %w", err)
 	}
 
 	return nil
 }
 
-func (wm *WorktreeManager) addGitWorktree(repoPath, worktreePath, branch string) error {
+func (wm *WorktreeManager) addGitWorktree(repoPath, worktreePath, branch, startPoint string, tx *addTransaction) error {
 	// Try to create a new branch first, then add worktree
 	createBranchCmd := fmt.Sprintf("git -C %s branch %s",
 		shellescape(repoPath),
 		shellescape(branch))
+	if startPoint != "" {
+		createBranchCmd += " " + shellescape(startPoint)
+	}
 
 	// Attempt to create new branch (will fail if branch already exists)
 	_, createErr := wm.runner.Run(createBranchCmd)
+	if createErr == nil {
+		tx.createdBranch = branch
+	} else if startPoint != "" {
+		// An existing branch would silently ignore the start point
+		return fmt.Errorf("failed to create branch %s from %s (it may already exist): %w", branch, startPoint, createErr)
+	}
 
 	// Now try to add worktree (works with both new and existing branches)
 	gitCmd := fmt.Sprintf("git -C %s worktree add %s %s",
@@ -253,10 +581,15 @@ func (wm *WorktreeManager) addGitWorktree(repoPath, worktreePath, branch string)
 	return nil
 }
 
-func (wm *WorktreeManager) removeGitWorktree(repoPath, worktreePath string) error {
+func (wm *WorktreeManager) removeGitWorktree(repoPath, worktreePath string, force bool) error {
 	gitCmd := fmt.Sprintf("git -C %s worktree remove %s",
 		shellescape(repoPath),
 		shellescape(worktreePath))
+	if force {
+		// Only used once the changes are backed up; this also deletes
+		// ignored files git would otherwise refuse to remove.
+		gitCmd += " --force"
+	}
 
 	if _, err := wm.runner.Run(gitCmd); err != nil {
 		return fmt.Errorf("git worktree remove failed: %w", err)
@@ -271,22 +604,6 @@ func shellescape(s string) string {
 	return s
 }
 
-func validateBranchName(branch string) error {
-	if branch == "" {
-		return fmt.Errorf("branch name cannot be empty")
-	}
-
-	if strings.ContainsAny(branch, ";&|`$(){}[]<>") {
-		return fmt.Errorf("invalid characters in branch name: %q", branch)
-	}
-
-	if strings.HasPrefix(branch, "-") {
-		return fmt.Errorf("branch name cannot start with dash: %q", branch)
-	}
-
-	return nil
-}
-
 func validatePath(path string) error {
 	if path == "" {
 		return fmt.Errorf("path cannot be empty")
@@ -299,6 +616,8 @@ func validatePath(path string) error {
 	return nil
 }
 
+// GenerateWorktreePath returns the worktree path for branch under the
+// default layout.
 func GenerateWorktreePath(repoPath, branch string) string {
 	worktreeName := BranchToWorktreeName(branch)
 	return filepath.Join(repoPath, "worktrees", worktreeName)
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -158,6 +160,44 @@ func TestWorktreeManager_AddWorktree_BranchFallback(t *testing.T) {
 	}
 }
 
+func TestWorktreeManager_AddWorktreeFrom(t *testing.T) {
+	tests := []struct {
+		name         string
+		branchExists bool
+		wantErr      bool
+	}{
+		{name: "new branch starts at the start point"},
+		{name: "existing branch is refused", branchExists: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRunner := &MockCommandRunner{outputs: map[string]string{
+				"mkdir -p /repo/worktrees": "",
+			}}
+			if !tt.branchExists {
+				mockRunner.outputs["git -C /repo branch fix origin/release"] = ""
+			}
+			mockRunner.outputs["git -C /repo worktree add /repo/worktrees/fix fix"] = ""
+			manager := NewWorktreeManager(NewGitService(mockRunner), mockRunner)
+
+			gotPath, err := manager.AddWorktreeFrom("/repo", "fix", "origin/release")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AddWorktreeFrom() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if slices.Contains(mockRunner.GetCommands(), "git -C /repo worktree add /repo/worktrees/fix fix") {
+					t.Error("worktree added on the existing branch")
+				}
+				return
+			}
+			if gotPath != "/repo/worktrees/fix" {
+				t.Errorf("AddWorktreeFrom() path = %v, want /repo/worktrees/fix", gotPath)
+			}
+		})
+	}
+}
+
 func TestWorktreeManager_AutoSetup(t *testing.T) {
 	// Test only the auto-setup functionality (directory creation + gitignore) without git commands
 	tempDir := t.TempDir()
@@ -217,7 +257,12 @@ func TestWorktreeManager_AutoSetup(t *testing.T) {
 			expectedWorktreePath := GenerateWorktreePath(testRepo, tt.branch)
 			worktreesDir := filepath.Dir(expectedWorktreePath)
 
-			err := manager.ensureWorktreesDirectory(worktreesDir)
+			layout, err := NewLayout(testRepo, DefaultPathTemplate)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = manager.ensureWorktreesDirectory(layout, worktreesDir)
 
 			// Check results - worktrees directory should be created
 			if tt.wantWorktreesDir {
@@ -278,9 +323,9 @@ func TestValidateBranchName(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:    "branch with semicolon",
+			name:    "branch with semicolon is valid in git",
 			branch:  "feature;dangerous",
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name:    "branch starting with dash",
@@ -288,8 +333,18 @@ func TestValidateBranchName(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:    "branch with backtick",
+			name:    "branch with backtick is valid in git",
 			branch:  "feature`dangerous",
+			wantErr: false,
+		},
+		{
+			name:    "branch with parentheses",
+			branch:  "fix(parser)",
+			wantErr: false,
+		},
+		{
+			name:    "branch with space",
+			branch:  "feature;rm -rf /",
 			wantErr: true,
 		},
 	}
@@ -403,6 +458,25 @@ func TestWorktreeManager_RemoveWorktree(t *testing.T) {
 			wantErr:   true,
 			errMsg:    "not found",
 		},
+		{
+			name:     "cannot remove worktree outside managed root",
+			repoPath: "/repo",
+			target:   "feature-auth",
+			worktrees: []Worktree{
+				{
+					Path:   "/repo",
+					Branch: "main",
+					Status: StatusClean,
+				},
+				{
+					Path:   "/repo/src/worktrees/feature-auth",
+					Branch: "feature/auth",
+					Status: StatusClean,
+				},
+			},
+			wantErr: true,
+			errMsg:  "outside the managed directory",
+		},
 	}
 
 	for _, tt := range tests {
@@ -463,6 +537,34 @@ func generateMockWorktreeOutput(worktrees []Worktree) string {
 	return strings.Join(parts, "\n\n")
 }
 
+func TestRemovableWorktrees(t *testing.T) {
+	layout, err := NewLayout("/repo", DefaultPathTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktrees := []Worktree{
+		{Path: "/repo", Branch: "main", Root: layout.Root(), Main: true},
+		{Path: "/repo/worktrees/clean", Branch: "clean", Root: layout.Root()},
+		{Path: "/repo/worktrees/dirty", Branch: "dirty", Root: layout.Root(), Status: StatusDirty},
+		{Path: "/repo/worktrees/locked", Branch: "locked", Root: layout.Root(), Locked: true},
+		{Path: "/elsewhere/outside", Branch: "outside", Root: layout.Root()},
+	}
+
+	names := func(worktrees []Worktree) []string {
+		var names []string
+		for _, wt := range worktrees {
+			names = append(names, wt.Name())
+		}
+		return names
+	}
+	if got, want := names(RemovableWorktrees(layout, worktrees, RemoveOptions{})), []string{"clean"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RemovableWorktrees() = %v, want %v", got, want)
+	}
+	if got, want := names(RemovableWorktrees(layout, worktrees, RemoveOptions{Force: true})), []string{"clean", "dirty"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RemovableWorktrees(Force) = %v, want %v", got, want)
+	}
+}
+
 func TestWorktreeManager_EnsureGitignoreEntry(t *testing.T) {
 	// Create temporary directory for test files
 	tempDir := t.TempDir()
@@ -517,7 +619,7 @@ func TestWorktreeManager_EnsureGitignoreEntry(t *testing.T) {
 			service := NewGitService(mockRunner)
 			manager := NewWorktreeManager(service, mockRunner)
 
-			err := manager.ensureGitignoreEntry(testDir)
+			err := manager.ensureGitignoreEntry(testDir, "worktrees/")
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ensureGitignoreEntry() error = %v, wantErr %v", err, tt.wantErr)
@@ -545,3 +647,472 @@ func TestWorktreeManager_EnsureGitignoreEntry(t *testing.T) {
 		})
 	}
 }
+
+func TestWorktreeManager_AddWorktree_Rollback(t *testing.T) {
+	tests := []struct {
+		name              string
+		branchExists      bool
+		existingGitignore string
+		wantBranchDelete  bool
+	}{
+		{
+			name:             "new branch and gitignore are removed",
+			branchExists:     false,
+			wantBranchDelete: true,
+		},
+		{
+			name:              "existing gitignore is restored",
+			branchExists:      false,
+			existingGitignore: "*.log\n",
+			wantBranchDelete:  true,
+		},
+		{
+			name:             "existing branch is kept",
+			branchExists:     true,
+			wantBranchDelete: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoPath := t.TempDir()
+			gitignorePath := filepath.Join(repoPath, ".gitignore")
+			if tt.existingGitignore != "" {
+				if err := os.WriteFile(gitignorePath, []byte(tt.existingGitignore), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			branchCmd := "git -C " + repoPath + " branch feature"
+			deleteCmd := "git -C " + repoPath + " branch -D feature"
+
+			// git worktree add is not mocked, so it fails
+			mockRunner := &MockCommandRunner{outputs: map[string]string{deleteCmd: ""}}
+			if !tt.branchExists {
+				mockRunner.outputs[branchCmd] = ""
+			}
+
+			service := NewGitService(mockRunner)
+			manager := NewWorktreeManager(service, mockRunner)
+
+			if _, err := manager.AddWorktree(repoPath, "feature"); err == nil {
+				t.Fatal("AddWorktree() expected error, got nil")
+			}
+
+			deleted := false
+			for _, cmd := range mockRunner.GetCommands() {
+				if cmd == deleteCmd {
+					deleted = true
+				}
+			}
+			if deleted != tt.wantBranchDelete {
+				t.Errorf("branch deleted = %v, want %v (commands: %v)", deleted, tt.wantBranchDelete, mockRunner.GetCommands())
+			}
+
+			if _, err := os.Stat(filepath.Join(repoPath, "worktrees")); !os.IsNotExist(err) {
+				t.Errorf("worktrees directory should have been removed, stat error = %v", err)
+			}
+
+			content, err := os.ReadFile(gitignorePath)
+			if tt.existingGitignore == "" {
+				if !os.IsNotExist(err) {
+					t.Errorf(".gitignore should have been removed, got %q", content)
+				}
+			} else if string(content) != tt.existingGitignore {
+				t.Errorf(".gitignore = %q, want %q", content, tt.existingGitignore)
+			}
+		})
+	}
+}
+
+func TestWorktreeManager_AddWorktree_RollbackKeepsExistingDirectory(t *testing.T) {
+	repoPath := t.TempDir()
+	worktreesDir := filepath.Join(repoPath, "worktrees")
+	if err := os.MkdirAll(filepath.Join(worktreesDir, "other"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	mockRunner := &MockCommandRunner{outputs: make(map[string]string)}
+	service := NewGitService(mockRunner)
+	manager := NewWorktreeManager(service, mockRunner)
+
+	if _, err := manager.AddWorktree(repoPath, "feature"); err == nil {
+		t.Fatal("AddWorktree() expected error, got nil")
+	}
+
+	if _, err := os.Stat(filepath.Join(worktreesDir, "other")); err != nil {
+		t.Errorf("pre-existing worktree directory should be kept: %v", err)
+	}
+}
+
+func TestWorktreeManager_RemoveWorktree_BareRepository(t *testing.T) {
+	mockRunner := &MockCommandRunner{
+		outputs: map[string]string{
+			"git worktree list --porcelain": "worktree /src/app.git\nbare\n\n" +
+				"worktree /src/app.worktrees/main\nHEAD abc123\nbranch refs/heads/main",
+			"git -C /src/app.git rev-parse --is-bare-repository":          "true\n",
+			"git -C /src/app.worktrees/main status --porcelain":           "",
+			"git -C /src/app.git worktree remove /src/app.worktrees/main": "",
+		},
+	}
+
+	service := NewGitService(mockRunner)
+	manager := NewWorktreeManager(service, mockRunner)
+
+	// Every branch, including main, is a linked worktree of a bare repository
+	if err := manager.RemoveWorktree("/src/app.git", "main"); err != nil {
+		t.Errorf("RemoveWorktree() unexpected error = %v", err)
+	}
+}
+
+// sequenceRunner returns queued outputs for commands whose result changes
+// between calls, falling back to MockCommandRunner for everything else.
+type sequenceRunner struct {
+	MockCommandRunner
+	sequences map[string][]string
+}
+
+func (s *sequenceRunner) Run(command string) (string, error) {
+	if queue := s.sequences[command]; len(queue) > 0 {
+		s.commands = append(s.commands, command)
+		s.sequences[command] = queue[1:]
+		return queue[0], nil
+	}
+	return s.MockCommandRunner.Run(command)
+}
+
+func TestWorktreeManager_RemoveWorktrees_Force(t *testing.T) {
+	const wtPath = "/repo/worktrees/feature-auth"
+	listOutput := "worktree /repo\nHEAD abc123\nbranch refs/heads/main\n\n" +
+		"worktree " + wtPath + "\nHEAD def456\nbranch refs/heads/feature/auth"
+
+	newRunner := func() *sequenceRunner {
+		return &sequenceRunner{
+			MockCommandRunner: MockCommandRunner{outputs: map[string]string{
+				"git worktree list --porcelain":   listOutput,
+				"git -C /repo status --porcelain": "",
+				"git -C " + wtPath + " stash push --include-untracked --message 'wt backup of feature-auth'": "",
+				"git -C /repo update-ref refs/wt/backups/feature-auth 1234abcd":                              "",
+				"git -C " + wtPath + " stash drop --quiet":                                                   "",
+				"git -C /repo worktree remove " + wtPath + " --force":                                        "",
+			}},
+			sequences: map[string][]string{
+				"git -C " + wtPath + " status --porcelain":                    {" M main.go\n?? notes.txt\n", ""},
+				"git -C " + wtPath + " rev-parse --verify --quiet refs/stash": {"", "1234abcd\n"},
+			},
+		}
+	}
+
+	t.Run("backs up changes before removing", func(t *testing.T) {
+		runner := newRunner()
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		results, err := manager.RemoveWorktrees("/repo", []string{"feature-auth"}, RemoveOptions{Force: true})
+		if err != nil {
+			t.Fatalf("RemoveWorktrees() error = %v (commands: %v)", err, runner.GetCommands())
+		}
+		want := RemoveResult{Name: "feature-auth", Path: wtPath, Branch: "feature/auth", BackupRef: "refs/wt/backups/feature-auth", Removed: true, head: "def456"}
+		if len(results) != 1 || results[0] != want {
+			t.Errorf("RemoveWorktrees() = %+v, want [%+v]", results, want)
+		}
+	})
+
+	t.Run("existing backup gets a new name", func(t *testing.T) {
+		runner := newRunner()
+		runner.outputs["git -C /repo rev-parse --verify --quiet refs/wt/backups/feature-auth"] = "5678\n"
+		runner.outputs["git -C /repo update-ref refs/wt/backups/feature-auth-2 1234abcd"] = ""
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		results, err := manager.RemoveWorktrees("/repo", []string{"feature-auth"}, RemoveOptions{Force: true})
+		if err != nil {
+			t.Fatalf("RemoveWorktrees() error = %v", err)
+		}
+		if results[0].BackupRef != "refs/wt/backups/feature-auth-2" {
+			t.Errorf("BackupRef = %q, want %q", results[0].BackupRef, "refs/wt/backups/feature-auth-2")
+		}
+	})
+
+	t.Run("failed backup removes nothing", func(t *testing.T) {
+		runner := newRunner()
+		delete(runner.outputs, "git -C "+wtPath+" stash push --include-untracked --message 'wt backup of feature-auth'")
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		_, err := manager.RemoveWorktrees("/repo", []string{"feature-auth"}, RemoveOptions{Force: true})
+		if err == nil || !strings.Contains(err.Error(), "failed to back up") {
+			t.Fatalf("RemoveWorktrees() error = %v, want backup error", err)
+		}
+		for _, cmd := range runner.GetCommands() {
+			if strings.Contains(cmd, "worktree remove") {
+				t.Errorf("worktree must not be removed when the backup fails, ran %q", cmd)
+			}
+		}
+	})
+
+	t.Run("leftover changes are restored", func(t *testing.T) {
+		runner := newRunner()
+		runner.sequences["git -C "+wtPath+" status --porcelain"] = []string{" M main.go\n", "?? build.log\n"}
+		runner.outputs["git -C "+wtPath+" stash apply refs/wt/backups/feature-auth"] = ""
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		_, err := manager.RemoveWorktrees("/repo", []string{"feature-auth"}, RemoveOptions{Force: true})
+		if err == nil || !strings.Contains(err.Error(), "changes remain") {
+			t.Fatalf("RemoveWorktrees() error = %v, want error about remaining changes", err)
+		}
+		commands := runner.GetCommands()
+		if last := commands[len(commands)-1]; last != "git -C "+wtPath+" stash apply refs/wt/backups/feature-auth" {
+			t.Errorf("last command = %q, want the backup to be applied again", last)
+		}
+	})
+
+	t.Run("dirty worktree needs force", func(t *testing.T) {
+		runner := newRunner()
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		_, err := manager.RemoveWorktrees("/repo", []string{"feature-auth"}, RemoveOptions{})
+		if err == nil || !strings.Contains(err.Error(), "--force") {
+			t.Errorf("RemoveWorktrees() error = %v, want error suggesting --force", err)
+		}
+	})
+}
+
+func TestWorktreeManager_RemoveWorktrees_DeleteBranch(t *testing.T) {
+	listOutput := "worktree /repo\nHEAD abc123\nbranch refs/heads/main\n\n" +
+		"worktree /repo/worktrees/feature-auth\nHEAD def456\nbranch refs/heads/feature/auth\n\n" +
+		"worktree /repo/worktrees/spike\nHEAD 789abc\nbranch refs/heads/spike\n\n" +
+		"worktree /repo/worktrees/review\nHEAD 456def\ndetached"
+
+	newRunner := func() *MockCommandRunner {
+		return &MockCommandRunner{outputs: map[string]string{
+			"git worktree list --porcelain":                                                          listOutput,
+			"git -C /repo status --porcelain":                                                        "",
+			"git -C /repo/worktrees/feature-auth status --porcelain":                                 "",
+			"git -C /repo/worktrees/spike status --porcelain":                                        "",
+			"git -C /repo/worktrees/review status --porcelain":                                       "",
+			"git -C /repo symbolic-ref --short HEAD":                                                 "main\n",
+			"git -C /repo merge-base --is-ancestor refs/heads/feature/auth refs/heads/main":          "",
+			"git -C /repo worktree remove /repo/worktrees/feature-auth":                              "",
+			"git -C /repo worktree remove /repo/worktrees/spike":                                     "",
+			"git -C /repo worktree remove /repo/worktrees/review":                                    "",
+			"git -C /repo/worktrees/feature-auth rev-list --count main..HEAD":                        "0\n",
+			"git -C /repo/worktrees/review rev-list --count main..HEAD":                              "0\n",
+			"git -C /repo/worktrees/spike rev-parse --abbrev-ref --symbolic-full-name '@{upstream}'": "origin/spike\n",
+			"git -C /repo/worktrees/spike rev-list --count origin/spike..HEAD":                       "0\n",
+			"git -C /repo branch -D feature/auth":                                                    "",
+			"git -C /repo branch -D spike":                                                           "",
+		}}
+	}
+
+	t.Run("merged branch is deleted after its worktree", func(t *testing.T) {
+		runner := newRunner()
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		results, err := manager.RemoveWorktrees("/repo", []string{"feature-auth"}, RemoveOptions{DeleteBranch: true})
+		if err != nil {
+			t.Fatalf("RemoveWorktrees() error = %v", err)
+		}
+		if !results[0].BranchDeleted {
+			t.Error("BranchDeleted = false, want true")
+		}
+		commands := runner.GetCommands()
+		removeAt := slices.Index(commands, "git -C /repo worktree remove /repo/worktrees/feature-auth")
+		deleteAt := slices.Index(commands, "git -C /repo branch -D feature/auth")
+		if removeAt < 0 || deleteAt < removeAt {
+			t.Errorf("branch must be deleted after the worktree, commands: %v", commands)
+		}
+	})
+
+	t.Run("detached worktree has no branch to delete", func(t *testing.T) {
+		runner := newRunner()
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		results, err := manager.RemoveWorktrees("/repo", []string{"review"}, RemoveOptions{DeleteBranch: true})
+		if err != nil {
+			t.Fatalf("RemoveWorktrees() error = %v", err)
+		}
+		if results[0].Branch != "" || results[0].BranchDeleted {
+			t.Errorf("result = %+v, want no branch", results[0])
+		}
+		for _, cmd := range runner.GetCommands() {
+			if strings.Contains(cmd, "merge-base") || strings.Contains(cmd, "branch -D") {
+				t.Errorf("a detached worktree has no branch to check or delete, ran %q", cmd)
+			}
+		}
+	})
+
+	t.Run("unmerged branch blocks every removal", func(t *testing.T) {
+		runner := newRunner()
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		_, err := manager.RemoveWorktrees("/repo", []string{"feature-auth", "spike"}, RemoveOptions{DeleteBranch: true})
+		if err == nil || !strings.Contains(err.Error(), `branch "spike" is not merged into main`) {
+			t.Fatalf("RemoveWorktrees() error = %v, want unmerged branch error", err)
+		}
+		for _, cmd := range runner.GetCommands() {
+			if strings.Contains(cmd, "worktree remove") || strings.Contains(cmd, "branch -D") {
+				t.Errorf("nothing may be removed when a branch would be kept, ran %q", cmd)
+			}
+		}
+	})
+
+	t.Run("force deletes unmerged branch", func(t *testing.T) {
+		runner := newRunner()
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		results, err := manager.RemoveWorktrees("/repo", []string{"spike"}, RemoveOptions{ForceDeleteBranch: true})
+		if err != nil {
+			t.Fatalf("RemoveWorktrees() error = %v", err)
+		}
+		if !results[0].BranchDeleted {
+			t.Error("BranchDeleted = false, want true")
+		}
+	})
+
+	t.Run("configured base branch", func(t *testing.T) {
+		runner := newRunner()
+		runner.outputs["git -C /repo config --get wt.baseBranch"] = "develop\n"
+		runner.outputs["git -C /repo merge-base --is-ancestor refs/heads/spike refs/heads/develop"] = ""
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		if _, err := manager.RemoveWorktrees("/repo", []string{"spike"}, RemoveOptions{DeleteBranch: true}); err != nil {
+			t.Errorf("RemoveWorktrees() error = %v", err)
+		}
+	})
+
+	t.Run("base branch is kept", func(t *testing.T) {
+		runner := newRunner()
+		runner.outputs["git -C /repo config --get wt.baseBranch"] = "spike\n"
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		_, err := manager.RemoveWorktrees("/repo", []string{"spike"}, RemoveOptions{ForceDeleteBranch: true})
+		if err == nil || !strings.Contains(err.Error(), "cannot delete base branch") {
+			t.Errorf("RemoveWorktrees() error = %v, want base branch error", err)
+		}
+	})
+}
+
+func TestWorktreeManager_RemoveWorktrees_PartialFailure(t *testing.T) {
+	listOutput := "worktree /repo\nHEAD abc123\nbranch refs/heads/main\n\n" +
+		"worktree /repo/worktrees/one\nHEAD 111111\nbranch refs/heads/one\n\n" +
+		"worktree /repo/worktrees/two\nHEAD 222222\ndetached\n\n" +
+		"worktree /repo/worktrees/three\nHEAD 333333\nbranch refs/heads/three"
+
+	// Removing "three" fails in every case below
+	newRunner := func() *MockCommandRunner {
+		return &MockCommandRunner{outputs: map[string]string{
+			"git worktree list --porcelain":                                 listOutput,
+			"git -C /repo status --porcelain":                               "",
+			"git -C /repo/worktrees/one status --porcelain":                 "",
+			"git -C /repo/worktrees/two status --porcelain":                 "",
+			"git -C /repo/worktrees/three status --porcelain":               "",
+			"git -C /repo worktree remove /repo/worktrees/one":              "",
+			"git -C /repo worktree remove /repo/worktrees/two":              "",
+			"git -C /repo worktree add /repo/worktrees/one one":             "",
+			"git -C /repo worktree add --detach /repo/worktrees/two 222222": "",
+		}}
+	}
+
+	t.Run("default mode restores removed worktrees", func(t *testing.T) {
+		runner := newRunner()
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		results, err := manager.RemoveWorktrees("/repo", []string{"one", "two", "three"}, RemoveOptions{})
+		if err == nil || !strings.Contains(err.Error(), "were restored") {
+			t.Fatalf("RemoveWorktrees() error = %v, want error reporting the restore", err)
+		}
+		for _, result := range results {
+			if result.Removed {
+				t.Errorf("%s should have been restored", result.Name)
+			}
+		}
+		if results[2].Err == nil {
+			t.Error("three should report its removal error")
+		}
+
+		commands := runner.GetCommands()
+		wantTail := []string{
+			"git -C /repo worktree add --detach /repo/worktrees/two 222222",
+			"git -C /repo worktree add /repo/worktrees/one one",
+		}
+		if got := commands[len(commands)-2:]; !reflect.DeepEqual(got, wantTail) {
+			t.Errorf("restore commands = %v, want %v", got, wantTail)
+		}
+	})
+
+	t.Run("failed restore is reported", func(t *testing.T) {
+		runner := newRunner()
+		delete(runner.outputs, "git -C /repo worktree add /repo/worktrees/one one")
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		results, err := manager.RemoveWorktrees("/repo", []string{"one", "three"}, RemoveOptions{})
+		if err == nil || !strings.Contains(err.Error(), "restore incomplete") {
+			t.Fatalf("RemoveWorktrees() error = %v, want incomplete restore", err)
+		}
+		if !results[0].Removed {
+			t.Error("one could not be restored and must be reported as removed")
+		}
+	})
+
+	t.Run("keep going removes what it can", func(t *testing.T) {
+		runner := newRunner()
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		results, err := manager.RemoveWorktrees("/repo", []string{"one", "missing", "three", "two"}, RemoveOptions{KeepGoing: true})
+		if err == nil || err.Error() != "2 of 4 worktrees failed" {
+			t.Fatalf("RemoveWorktrees() error = %v, want summary of 2 failures", err)
+		}
+
+		wantRemoved := []bool{true, false, false, true}
+		for i, result := range results {
+			if result.Removed != wantRemoved[i] {
+				t.Errorf("%s: Removed = %v, want %v", result.Name, result.Removed, wantRemoved[i])
+			}
+			if (result.Err != nil) == wantRemoved[i] {
+				t.Errorf("%s: Err = %v", result.Name, result.Err)
+			}
+		}
+		for _, cmd := range runner.GetCommands() {
+			if strings.Contains(cmd, "worktree add") {
+				t.Errorf("keep going must not restore worktrees, ran %q", cmd)
+			}
+		}
+	})
+
+	t.Run("keep going skips branch deletion of failed worktrees", func(t *testing.T) {
+		runner := newRunner()
+		runner.outputs["git -C /repo symbolic-ref --short HEAD"] = "main\n"
+		runner.outputs["git -C /repo/worktrees/one rev-list --count main..HEAD"] = "0\n"
+		runner.outputs["git -C /repo/worktrees/three rev-list --count main..HEAD"] = "0\n"
+		runner.outputs["git -C /repo branch -D one"] = ""
+		manager := NewWorktreeManager(NewGitService(runner), runner)
+
+		results, err := manager.RemoveWorktrees("/repo", []string{"one", "three"}, RemoveOptions{KeepGoing: true, ForceDeleteBranch: true})
+		if err == nil {
+			t.Fatal("RemoveWorktrees() expected error, got nil")
+		}
+		if !results[0].BranchDeleted || results[1].BranchDeleted {
+			t.Errorf("BranchDeleted = %v, %v, want true, false", results[0].BranchDeleted, results[1].BranchDeleted)
+		}
+	})
+}
+
+func TestWorktreeManager_RemoveWorktrees_DryRun(t *testing.T) {
+	runner := &MockCommandRunner{outputs: map[string]string{
+		"git worktree list --porcelain": "worktree /repo\nHEAD abc123\nbranch refs/heads/main\n\n" +
+			"worktree /repo/worktrees/feature\nHEAD def456\nbranch refs/heads/feature",
+		"git -C /repo status --porcelain":                   "",
+		"git -C /repo/worktrees/feature status --porcelain": "",
+	}}
+	manager := NewWorktreeManager(NewGitService(runner), runner)
+
+	results, err := manager.RemoveWorktrees("/repo", []string{"feature"}, RemoveOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("RemoveWorktrees() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Path != "/repo/worktrees/feature" || results[0].Removed {
+		t.Errorf("RemoveWorktrees() = %+v, want the unremoved feature worktree", results)
+	}
+
+	if _, err := manager.RemoveWorktrees("/repo", []string{"main"}, RemoveOptions{DryRun: true}); err == nil {
+		t.Error("dry run must still validate targets")
+	}
+}
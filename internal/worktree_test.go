@@ -71,6 +71,33 @@ func TestWorktree_Name(t *testing.T) {
 			},
 			want: "feature-auth",
 		},
+		{
+			name: "directory name wins inside managed root",
+			worktree: Worktree{
+				Branch: "renamed",
+				Path:   "/repo.worktrees/feature-auth",
+				Root:   "/repo.worktrees",
+			},
+			want: "feature-auth",
+		},
+		{
+			name: "nested branch layout",
+			worktree: Worktree{
+				Branch: "feature/auth",
+				Path:   "/repo/.worktrees/feature/auth",
+				Root:   "/repo/.worktrees",
+			},
+			want: "feature-auth",
+		},
+		{
+			name: "outside managed root uses branch",
+			worktree: Worktree{
+				Branch: "feature/auth",
+				Path:   "/elsewhere/worktrees/checkout",
+				Root:   "/repo/worktrees",
+			},
+			want: "feature-auth",
+		},
 	}
 
 	for _, tt := range tests {
@@ -118,3 +145,37 @@ func TestBranchToWorktreeName(t *testing.T) {
 		})
 	}
 }
+
+func TestFindWorktreeByPath(t *testing.T) {
+	worktrees := []Worktree{
+		{Path: "/repo.git", Bare: true},
+		{Path: "/repo", Branch: "main"},
+		{Path: "/repo/worktrees/feature", Branch: "feature"},
+		{Path: "/repo/worktrees/feature-ui", Branch: "feature-ui"},
+	}
+
+	tests := []struct {
+		dir    string
+		want   string
+		wantOK bool
+	}{
+		{dir: "/repo/worktrees/feature", want: "/repo/worktrees/feature", wantOK: true},
+		{dir: "/repo/worktrees/feature/src/pkg", want: "/repo/worktrees/feature", wantOK: true},
+		{dir: "/repo/worktrees/feature-ui", want: "/repo/worktrees/feature-ui", wantOK: true},
+		{dir: "/repo/worktrees", want: "/repo", wantOK: true},
+		{dir: "/repo.git/objects", wantOK: false},
+		{dir: "/elsewhere", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			got, ok := FindWorktreeByPath(worktrees, tt.dir)
+			if ok != tt.wantOK {
+				t.Fatalf("FindWorktreeByPath(%q) ok = %v, want %v", tt.dir, ok, tt.wantOK)
+			}
+			if ok && got.Path != tt.want {
+				t.Errorf("FindWorktreeByPath(%q) = %q, want %q", tt.dir, got.Path, tt.want)
+			}
+		})
+	}
+}